@@ -0,0 +1,399 @@
+package broccli
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// binding connects a struct field with a flag or an arg.
+type binding struct {
+	kind  int8
+	name  string
+	field reflect.Value
+}
+
+// Bind adds flags and args to a command from fields of a struct that target points to, and fills these fields with
+// values once the command line is validated.  Fields are defined with the following tags:
+//   - `flag:"name"` or `arg:"name"` sets the kind and the name of the param; fields without any of them are skipped,
+//   - `alias:"n"`, `placeholder:"NAME"`, `usage:"..."` are the same as in Flag and Arg,
+//   - `type:"..."` is one of string, bool, int, float, alphanumeric, path, duration, time, url, ip, cidr, size or
+//     regexp; by default it is taken from the field type,
+//   - `required:"true"`, `existent:"true"`, `multiple:"true"` and `repeated:"true"` add IsRequired, IsExistent,
+//     AllowMultipleValues and AllowRepeated,
+//   - `variadic:"true"` makes a slice arg capture all the remaining args, see Variadic,
+//   - `sep:":"` sets the separator of multiple values to one of ',', ':' or ';',
+//   - `env:"NAME1,NAME2"`, `default:"value"` and `choices:"json,yaml"` are the same as FromEnv, WithDefault and
+//     OneOf options,
+//   - `min:"1"` and `max:"10"` are the same as Min and Max options,
+//   - `sensitive:"true"` is the same as Sensitive option.
+//
+// Values bound to integer fields must fit in the field, eg. int8 field takes values from -128 to 127.
+// Fields can be of string, bool, integer and float types, time.Duration, time.Time, *url.URL, netip.Addr,
+// netip.Prefix, *regexp.Regexp, or slices of these (which allow multiple values).  Size requires int64 field.
+// Function panics when target is not a pointer to a struct or when tags are not valid.
+//
+//	type startOptions struct {
+//		Words string `flag:"words" alias:"w" usage:"Text file with words" type:"path" required:"true" existent:"true"`
+//		Level int    `arg:"level" placeholder:"LEVEL" usage:"Level of difficulty" default:"1"`
+//	}
+func (c *Command) Bind(target interface{}) {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("target of command %s must be a pointer to a struct", c.name))
+	}
+
+	c.bindStruct(value.Elem())
+}
+
+func (c *Command) bindStruct(value reflect.Value) {
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+
+		// fields of embedded structs are bound as well, even when the struct type is not exported
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			c.bindStruct(value.Field(i))
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, isFlag := field.Tag.Lookup("flag")
+		if !isFlag {
+			var isArg bool
+
+			name, isArg = field.Tag.Lookup("arg")
+			if !isArg {
+				continue
+			}
+		}
+
+		if name == "" {
+			panic(fmt.Sprintf("field %s of command %s has empty param name", field.Name, c.name))
+		}
+
+		valueType, flags := bindTypeAndFlags(field)
+
+		placeholder, ok := field.Tag.Lookup("placeholder")
+		if !ok && valueType != TypeBool {
+			placeholder = strings.ToUpper(name)
+		}
+
+		opts := []ParamOption{}
+		if defaultValue, ok := field.Tag.Lookup("default"); ok {
+			opts = append(opts, WithDefault(defaultValue))
+		}
+
+		if envVars, ok := field.Tag.Lookup("env"); ok {
+			opts = append(opts, FromEnv(strings.Split(envVars, ",")...))
+		}
+
+		if choices, ok := field.Tag.Lookup("choices"); ok {
+			opts = append(opts, OneOf(strings.Split(choices, ",")...))
+		}
+
+		opts = append(opts, bindBounds(field, valueType)...)
+
+		if isSensitive, _ := strconv.ParseBool(field.Tag.Get("sensitive")); isSensitive {
+			opts = append(opts, Sensitive())
+		}
+
+		if isVariadic, _ := strconv.ParseBool(field.Tag.Get("variadic")); isVariadic {
+			if isFlag || field.Type.Kind() != reflect.Slice {
+				panic(fmt.Sprintf("field %s must be a slice arg to be variadic", field.Name))
+			}
+
+			opts = append(opts, Variadic(0, 0))
+		}
+
+		kind := int8(ParamArg)
+		if isFlag {
+			kind = ParamFlag
+			c.Flag(name, field.Tag.Get("alias"), placeholder, field.Tag.Get("usage"), valueType, flags, opts...)
+		} else {
+			c.Arg(name, placeholder, field.Tag.Get("usage"), valueType, flags, opts...)
+		}
+
+		c.bindings = append(c.bindings, binding{kind: kind, name: name, field: value.Field(i)})
+	}
+}
+
+// bindTypeAndFlags returns value type and validation flags of param from tags and type of the field.
+func bindTypeAndFlags(field reflect.StructField) (int64, int64) {
+	var flags int64
+
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Slice {
+		fieldType = fieldType.Elem()
+		flags |= AllowMultipleValues
+	}
+
+	valueType := bindTypeFromType(fieldType)
+	if typeName := field.Tag.Get("type"); typeName != "" {
+		var ok bool
+
+		valueType, ok = bindTypes[typeName]
+		if !ok {
+			panic(fmt.Sprintf("field %s has invalid type: %s", field.Name, typeName))
+		}
+	}
+
+	if !bindTypeMatches(valueType, fieldType) {
+		panic(fmt.Sprintf("field %s is of %s type that cannot hold the param value", field.Name, field.Type.String()))
+	}
+
+	for tag, flag := range bindFlags {
+		if isSet, _ := strconv.ParseBool(field.Tag.Get(tag)); isSet {
+			flags |= flag
+		}
+	}
+
+	if flags&AllowRepeated > 0 && field.Type.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("field %s must be a slice to hold values of repeated flag", field.Name))
+	}
+
+	switch field.Tag.Get("sep") {
+	case "", ",":
+	case ":":
+		flags |= SeparatorColon
+	case ";":
+		flags |= SeparatorSemiColon
+	default:
+		panic(fmt.Sprintf("field %s has invalid separator: %s", field.Name, field.Tag.Get("sep")))
+	}
+
+	return valueType, flags
+}
+
+// bindBounds returns options with bounds of numeric param from 'min' and 'max' tags and bit size of integer field.
+func bindBounds(field reflect.StructField, valueType int64) []ParamOption {
+	opts := []ParamOption{}
+
+	for tag, option := range map[string]func(float64) ParamOption{"min": Min, "max": Max} {
+		value, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+
+		bound, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			panic(fmt.Sprintf("field %s has invalid %s: %s", field.Name, tag, value))
+		}
+
+		opts = append(opts, option(bound))
+	}
+
+	if valueType != TypeInt {
+		return opts
+	}
+
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Slice {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		opts = append(opts, BitSize(fieldType.Bits()))
+	}
+
+	return opts
+}
+
+// bindFlags maps boolean tags to validation flags.
+var bindFlags = map[string]int64{
+	"required": IsRequired,
+	"existent": IsExistent,
+	"multiple": AllowMultipleValues,
+	"repeated": AllowRepeated,
+}
+
+// bindTypes maps values of 'type' tag to value types.
+var bindTypes = map[string]int64{
+	"string":       TypeString,
+	"bool":         TypeBool,
+	"int":          TypeInt,
+	"float":        TypeFloat,
+	"alphanumeric": TypeAlphanumeric,
+	"path":         TypePathFile,
+	"duration":     TypeDuration,
+	"time":         TypeTime,
+	"url":          TypeURL,
+	"ip":           TypeIP,
+	"cidr":         TypeCIDR,
+	"size":         TypeSize,
+	"regexp":       TypeRegexp,
+}
+
+// bindTypedFields maps types of fields to value types that can be set only to fields of that type.
+var bindTypedFields = map[reflect.Type]int64{
+	reflect.TypeOf(time.Duration(0)): TypeDuration,
+	reflect.TypeOf(time.Time{}):      TypeTime,
+	reflect.TypeOf(&url.URL{}):       TypeURL,
+	reflect.TypeOf(netip.Addr{}):     TypeIP,
+	reflect.TypeOf(netip.Prefix{}):   TypeCIDR,
+	reflect.TypeOf(&regexp.Regexp{}): TypeRegexp,
+}
+
+func bindTypeFromType(fieldType reflect.Type) int64 {
+	if valueType, ok := bindTypedFields[fieldType]; ok {
+		return valueType
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		return TypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return TypeInt
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat
+	default:
+		return TypeString
+	}
+}
+
+func bindTypeMatches(valueType int64, fieldType reflect.Type) bool {
+	if typedFieldValueType, ok := bindTypedFields[fieldType]; ok {
+		return valueType == typedFieldValueType
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		return valueType != TypeBool
+	case reflect.Bool:
+		return valueType == TypeBool
+	case reflect.Int64:
+		return valueType == TypeInt || valueType == TypeSize
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return valueType == TypeInt
+	case reflect.Float32, reflect.Float64:
+		return valueType == TypeFloat || valueType == TypeInt
+	default:
+		return false
+	}
+}
+
+// populateBindings sets fields bound to flags and args of the command and its parents to the parsed values.
+func (c *Broccli) populateBindings(cmd *Command) error {
+	for bound := cmd; bound != nil; bound = bound.parent {
+		for _, b := range bound.bindings {
+			var (
+				p      *param
+				value  string
+				values []string
+			)
+
+			if b.kind == ParamFlag {
+				p, value = bound.flags[b.name], c.parsedFlags[b.name]
+				values = c.flagValues(p)
+			} else if bound == cmd {
+				p, value = bound.args[b.name], c.parsedArgs[b.name]
+				values = c.argValues(p)
+			} else {
+				continue
+			}
+
+			err := setField(b.field, p, value, values)
+			if err != nil {
+				paramErr := &ParamError{
+					Kind:  b.kind,
+					Name:  b.name,
+					Value: p.displayValue(value),
+					Err:   p.maskError(err, value),
+				}
+				if b.kind == ParamArg {
+					paramErr.label = p.valuePlaceholder
+				}
+
+				return paramErr
+			}
+		}
+	}
+
+	return nil
+}
+
+// setField sets field to the value of param, or to its values split into a slice when field is a slice.
+func setField(field reflect.Value, p *param, value string, values []string) error {
+	if field.Kind() != reflect.Slice {
+		return setFieldValue(field, p, value)
+	}
+
+	slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+
+	for i, v := range values {
+		err := setFieldValue(slice.Index(i), p, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	field.Set(slice)
+
+	return nil
+}
+
+func setFieldValue(field reflect.Value, p *param, value string) error {
+	if isTypedValue(p.valueType) && field.Kind() != reflect.String {
+		// field can hold a value from the previous run
+		if value == "" {
+			field.Set(reflect.Zero(field.Type()))
+
+			return nil
+		}
+
+		parsed, err := parseTypedValue(p.valueType, value)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(parsed).Convert(field.Type()))
+
+		return nil
+	}
+
+	//nolint:exhaustive
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := p.boolValue(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := p.intValue(value)
+		if err != nil {
+			return err
+		}
+
+		if field.OverflowInt(int64(i)) {
+			return fmt.Errorf("%w: %s overflows %s", ErrParamValueInvalid, value, field.Type().String())
+		}
+
+		field.SetInt(int64(i))
+	case reflect.Float32, reflect.Float64:
+		f, err := p.floatValue(value)
+		if err != nil {
+			return err
+		}
+
+		if field.OverflowFloat(f) {
+			return fmt.Errorf("%w: %s overflows %s", ErrParamValueInvalid, value, field.Type().String())
+		}
+
+		field.SetFloat(f)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package broccli
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+type bindTestCommon struct {
+	Verbose bool `flag:"verbose" alias:"v" usage:"Verbose output"`
+}
+
+type bindTestOptions struct {
+	bindTestCommon
+
+	Text   string        `flag:"text" alias:"t" usage:"Text to print" required:"true"`
+	Count  int8          `flag:"count" alias:"c" usage:"Number of repeats" default:"3"`
+	Ratio  float64       `flag:"ratio" usage:"Ratio" env:"RATIO"`
+	Levels []int         `flag:"levels" usage:"Levels" sep:":"`
+	Tags   []string      `flag:"tag" usage:"Tags" repeated:"true"`
+	Wait   time.Duration `flag:"wait" usage:"Wait" default:"1m"`
+	Name   string        `arg:"name" usage:"Name" type:"alphanumeric"`
+	Values []float64     `arg:"values" placeholder:"VALUES" usage:"Values"`
+
+	ignored string
+}
+
+// TestBind tests defining a command from a struct and populating it.
+func TestBind(t *testing.T) {
+	t.Parallel()
+
+	cli := newTestCLI(WithLookupEnv(func(name string) (string, bool) {
+		if name == "RATIO" {
+			return "0.5", true
+		}
+
+		return "", false
+	}))
+
+	opts := bindTestOptions{}
+	cmd := cli.Command("cmd", "Prints text", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd.Bind(&opts)
+
+	if cmd.flags["text"].flags&IsRequired == 0 || cmd.flags["count"].valueType != TypeInt {
+		t.Errorf("Bind() defined invalid flags")
+	}
+
+	if cmd.args["name"].valuePlaceholder != "NAME" || cmd.args["name"].valueType != TypeAlphanumeric {
+		t.Errorf("Bind() defined invalid args")
+	}
+
+	_, err := cli.Parse([]string{
+		"test", "cmd", "-v", "-t", "hello", "--levels", "1:2", "--tag", "a", "--tag", "b", "john", "1.5,2.0",
+	})
+	if err != nil {
+		t.Fatalf("Parse() returned error: %s", err.Error())
+	}
+
+	if !opts.Verbose || opts.Text != "hello" || opts.Count != 3 || opts.Ratio != 0.5 || opts.Name != "john" {
+		t.Errorf("Parse() populated invalid values: %+v", opts)
+	}
+
+	if len(opts.Levels) != 2 || opts.Levels[1] != 2 || len(opts.Values) != 2 || opts.Values[0] != 1.5 ||
+		len(opts.Tags) != 2 || opts.Tags[1] != "b" || opts.Wait != time.Minute {
+		t.Errorf("Parse() populated invalid slices: %+v", opts)
+	}
+
+	_, err = cli.Parse([]string{"test", "cmd", "-t", "hello", "-c", "300"})
+	if err == nil || err.Error() != "Flag count: param value invalid: 300 is out of range of 8-bit integer" {
+		t.Errorf("Parse() should have returned overflow error instead of %v", err)
+	}
+}
+
+// TestBindInvalidStruct tests that Bind panics on invalid tags.
+func TestBindInvalidStruct(t *testing.T) {
+	t.Parallel()
+
+	invalidTargets := []interface{}{
+		bindTestOptions{},
+		&struct {
+			Count int `flag:"count" type:"path"`
+		}{},
+		&struct {
+			Count int `flag:"count" type:"number"`
+		}{},
+		&struct {
+			Items []string `flag:"items" sep:"|"`
+		}{},
+		&struct {
+			Tag string `flag:"tag" repeated:"true"`
+		}{},
+		&struct {
+			Count int `flag:"count" min:"one"`
+		}{},
+	}
+
+	for _, target := range invalidTargets {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Bind() should have panicked for %T", target)
+				}
+			}()
+
+			cmd := newCommand("cmd", "Does something", nil)
+			cmd.Bind(target)
+		}()
+	}
+}
+
+// TestBindResetsFields tests that fields of params that are not passed are reset when command line is parsed again.
+func TestBindResetsFields(t *testing.T) {
+	t.Parallel()
+
+	var opts struct {
+		Timeout time.Duration `flag:"timeout"`
+		Address netip.Addr    `flag:"address"`
+		Name    string        `flag:"name"`
+	}
+
+	cli := newTestCLI()
+	cmd := cli.Command("bind", "Binds", func(_ context.Context, _ *Broccli) int { return 0 })
+	cmd.Bind(&opts)
+
+	_, err := cli.Parse([]string{"test", "bind", "--timeout", "5s", "--address", "10.0.0.1", "--name", "x"})
+	if err != nil || opts.Timeout != 5*time.Second || !opts.Address.IsValid() || opts.Name != "x" {
+		t.Fatalf("Parse() populated invalid values: %+v, %v", opts, err)
+	}
+
+	_, err = cli.Parse([]string{"test", "bind"})
+	if err != nil || opts.Timeout != 0 || opts.Address.IsValid() || opts.Name != "" {
+		t.Errorf("Parse() should have reset the fields: %+v, %v", opts, err)
+	}
+}
@@ -1,14 +1,16 @@
 package broccli
 
 import (
+	"bufio"
 	"context"
-	"flag"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 )
@@ -25,10 +27,30 @@ type Broccli struct {
 	env         map[string]*param
 	parsedFlags map[string]string
 	parsedArgs  map[string]string
+	// parsedRepeated contains values of each occurrence of flags with AllowRepeated
+	parsedRepeated map[string][]string
+	// parsedVariadic contains values of variadic args
+	parsedVariadic map[string][]string
+	// passedFlags contains names of flags that were set by the user, ie. the value does not come from the default
+	passedFlags  map[string]bool
+	parsedEnv    map[string]string
+	command      *Command
+	configFlag   *param
+	configValues map[string]configValue
+	configPath   string
+	// global holds flags that are available in every command
+	global      *Command
+	programName string
+	options     broccliOptions
+	// input reads answers when prompting for missing values
+	input *bufio.Reader
+	// warnings contains messages about deprecated command and flags that were used
+	warnings []string
 }
 
 // NewBroccli returns pointer to a new Broccli instance.  Name, usage and author are displayed on the syntax screen.
-func NewBroccli(name, usage, author string) *Broccli {
+// Additionally, there is a set of options that can be passed as arguments.  Search for BroccliOption for more info.
+func NewBroccli(name, usage, author string, opts ...BroccliOption) *Broccli {
 	cli := &Broccli{
 		name:        name,
 		usage:       usage,
@@ -37,127 +59,414 @@ func NewBroccli(name, usage, author string) *Broccli {
 		env:         map[string]*param{},
 		parsedFlags: map[string]string{},
 		parsedArgs:  map[string]string{},
+		parsedEnv:   map[string]string{},
+		global:      newCommand("", "", nil),
+		programName: name,
+		options:     defaultBroccliOptions(),
+	}
+	for _, opt := range opts {
+		opt(&(cli.options))
 	}
 
 	return cli
 }
 
 // Command returns pointer to a new command with specified name, usage and handler.  Handler is a function that
-// gets called when command is executed.
+// gets called when command is executed.  Command can have its own subcommands, see Command.Command.
 // Additionally, there is a set of options that can be passed as arguments.  Search for commandOption for more info.
 func (c *Broccli) Command(
 	name, usage string,
 	handler func(ctx context.Context, cli *Broccli) int,
 	opts ...CommandOption,
 ) *Command {
-	c.commands[name] = &Command{
-		name:    name,
-		usage:   usage,
-		flags:   map[string]*param{},
-		args:    map[string]*param{},
-		env:     map[string]*param{},
-		handler: handler,
-		options: commandOptions{},
-	}
-	for _, opt := range opts {
-		opt(&(c.commands[name].options))
-	}
+	c.commands[name] = newCommand(name, usage, handler, opts...)
+	c.commands[name].global = c.global
 
 	return c.commands[name]
 }
 
+// GlobalFlag adds a flag that is available in every command, eg. '--verbose'.  It takes the same arguments as
+// Command.Flag.  Global flag can be passed before or after the command name, and its value is available with Flag
+// and similar methods in every handler.  Flag of a command with the same name takes precedence over the global one.
+func (c *Broccli) GlobalFlag(
+	name, alias, valuePlaceholder, usage string,
+	types, flags int64,
+	opts ...ParamOption,
+) {
+	c.global.Flag(name, alias, valuePlaceholder, usage, types, flags, opts...)
+}
+
 // Env returns pointer to a new environment variable that is required to run every command.
 // Method requires name, eg. MY_VAR, and usage.
-func (c *Broccli) Env(name string, usage string) {
+func (c *Broccli) Env(name string, usage string, opts ...ParamOption) {
 	c.env[name] = &param{
 		name:    name,
 		usage:   usage,
 		flags:   IsRequired,
 		options: paramOptions{},
 	}
+	for _, opt := range opts {
+		opt(&(c.env[name].options))
+	}
+
+	c.env[name].mustBeValid()
 }
 
-// Flag returns value of flag.
+// Flag returns value of flag.  Values of flag with AllowRepeated are joined with its separator, use FlagStrings to
+// get them as a slice.
 func (c *Broccli) Flag(name string) string {
 	return c.parsedFlags[name]
 }
 
-// Arg returns value of arg.
+// Arg returns value of arg.  Values of variadic arg are joined with its separator, use ArgStrings to get them as
+// a slice.
 func (c *Broccli) Arg(name string) string {
 	return c.parsedArgs[name]
 }
 
-// Run parses the arguments, validates them and executes command handler.
+// Stdout returns writer that handlers should write their output to.
+func (c *Broccli) Stdout() io.Writer {
+	return c.options.stdout
+}
+
+// Stderr returns writer that handlers should write their errors to.
+func (c *Broccli) Stderr() io.Writer {
+	return c.options.stderr
+}
+
+// Stdin returns reader that handlers should read their input from.
+func (c *Broccli) Stdin() io.Reader {
+	return c.options.stdin
+}
+
+// Run parses the arguments from os.Args, validates them and executes command handler.
 // In case of invalid arguments, error is printed to stderr and 1 is returned.  Return value should be treated as exit
 // code.
 func (c *Broccli) Run(ctx context.Context) int {
-	// display help, first arg is binary filename
-	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" {
-		c.printHelp()
+	return c.RunArgs(ctx, os.Args)
+}
+
+// RunArgs works the same as Run but takes the arguments from args instead of os.Args.  First element of args is
+// the program name, the same as in os.Args.
+func (c *Broccli) RunArgs(ctx context.Context, args []string) int {
+	// completion scripts call the program to get the candidates
+	if len(args) > 1 && args[1] == completeCommandName {
+		c.programName = path.Base(args[0])
+		c.printCompletions(args[2:])
 
 		return 0
 	}
 
-	for _, commandName := range c.sortedCommands() {
-		if commandName != os.Args[1] {
-			continue
+	cmd, err := c.Parse(args)
+	if err != nil {
+		return c.handleError(cmd, err)
+	}
+
+	for _, warning := range c.warnings {
+		fmt.Fprintf(c.options.stderr, "WARNING: %s\n", warning)
+	}
+
+	exitCode := cmd.handler(ctx, c)
+	c.clearSensitive(cmd)
+
+	return exitCode
+}
+
+// Execute parses the arguments, validates them and executes command handler.  It does not print anything, and warnings
+// about deprecated command and flags are available with Warnings.  Returned int is the exit code from the handler.
+// Error is returned when arguments are not valid, see Parse for details.
+func (c *Broccli) Execute(ctx context.Context, args []string) (int, error) {
+	cmd, err := c.Parse(args)
+	if err != nil {
+		return 0, err
+	}
+
+	exitCode := cmd.handler(ctx, c)
+	c.clearSensitive(cmd)
+
+	return exitCode, nil
+}
+
+// Parse parses the arguments, validates them and returns the command that should be run.  Values of flags and args
+// are available with Flag, Arg and similar methods once it succeeds.  First element of args is the program name,
+// the same as in os.Args.
+// When help screen is requested, ErrHelp is returned.  Invalid command name results in ErrCommandInvalid, and
+// invalid flag, arg or environment variable results in *ParamError.  Error from OnPostValidation is returned as it
+// is.  In case of an error, returned command is the one whose help screen applies, or nil for the main help screen.
+func (c *Broccli) Parse(args []string) (*Command, error) {
+	c.parsedFlags = map[string]string{}
+	c.parsedRepeated = map[string][]string{}
+	c.parsedVariadic = map[string][]string{}
+	c.passedFlags = map[string]bool{}
+	c.parsedArgs = map[string]string{}
+	c.parsedEnv = map[string]string{}
+	c.command = nil
+	c.warnings = []string{}
+
+	if len(args) > 0 {
+		c.programName = path.Base(args[0])
+	}
+
+	// global flags can be passed before the command name, first arg is binary filename
+	cmdIdx, err := c.skipGlobalFlags(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// display help
+	if len(args) <= cmdIdx || args[cmdIdx] == "-h" || args[cmdIdx] == "--help" {
+		return nil, ErrHelp
+	}
+
+	cmd := findCommand(c.commands, args[cmdIdx])
+	if cmd == nil {
+		return nil, errCommandInvalidWithName(args[cmdIdx], c.commands)
+	}
+
+	// walk down the tree of subcommands, flags known to a command can be passed before its subcommand
+	argIdx := cmdIdx + 1
+	flagArgs := append([]string{}, args[1:cmdIdx]...)
+
+	for argIdx < len(args) {
+		nameIdx, err := skipFlags(c.commandFlags(cmd), args, argIdx)
+		if err != nil || nameIdx >= len(args) {
+			break
 		}
-		// display command help
-		if len(os.Args) > 2 && (os.Args[2] == "-h" || os.Args[2] == "--help") {
-			c.commands[commandName].printHelp()
 
-			return 0
+		subcommand := findCommand(cmd.commands, args[nameIdx])
+		if subcommand == nil {
+			break
 		}
 
-		// check required environment variables
-		if len(c.env) > 0 {
-			for env, param := range c.env {
-				envValue := os.Getenv(env)
-				param.flags |= IsRequired
+		flagArgs = append(flagArgs, args[argIdx:nameIdx]...)
+		cmd = subcommand
+		argIdx = nameIdx + 1
+	}
 
-				err := param.validateValue(envValue)
-				if err != nil {
-					fmt.Fprintf(
-						os.Stderr,
-						"ERROR: %s %s: %s\n",
-						c.getParamTypeName(ParamEnvVar),
-						param.name,
-						err.Error(),
-					)
-					c.printHelp()
+	// display command help
+	if len(args) > argIdx && (args[argIdx] == "-h" || args[argIdx] == "--help") {
+		return cmd, ErrHelp
+	}
 
-					return 1
-				}
-			}
+	// command only groups subcommands
+	if cmd.handler == nil {
+		return cmd, c.groupCommandError(cmd, args, argIdx)
+	}
+
+	// check required environment variables
+	envErr := c.checkEnv(c.env, c.sortedEnv())
+	if envErr != nil && !c.options.allErrors {
+		return nil, envErr
+	}
+
+	// parse and validate all the flags and args
+	c.command = cmd
+
+	cmdArgs := append(flagArgs, args[argIdx:]...)
+
+	err = c.parseFlags(cmd, cmdArgs)
+	if err != nil || envErr != nil {
+		return cmd, errors.Join(envErr, err)
+	}
+
+	// fill structs bound to the command
+	err = c.populateBindings(cmd)
+	if err != nil {
+		return cmd, err
+	}
+
+	c.addDeprecationWarnings(cmd)
+
+	return cmd, nil
+}
+
+// Warnings returns messages about deprecated command and flags that were used in the arguments passed to the last
+// Parse.  RunArgs prints them to the error output before running the handler.
+func (c *Broccli) Warnings() []string {
+	return c.warnings
+}
+
+// addDeprecationWarnings adds warnings when the command, any of its parents or passed flags are deprecated.
+func (c *Broccli) addDeprecationWarnings(cmd *Command) {
+	commands := []*Command{}
+	for parent := cmd; parent != nil; parent = parent.parent {
+		commands = append([]*Command{parent}, commands...)
+	}
+
+	for _, deprecated := range commands {
+		if deprecated.options.deprecated {
+			c.warnings = append(c.warnings, deprecationWarning(
+				"command "+deprecated.fullName(),
+				deprecated.options.deprecation,
+			))
 		}
+	}
 
-		// parse and validate all the flags and args
-		exitCode := c.parseFlags(c.commands[commandName])
-		if exitCode > 0 {
-			return exitCode
+	flags := cmd.allFlags()
+	for _, name := range cmd.sortedAllFlags() {
+		if flags[name].options.deprecated && c.passedFlags[name] {
+			c.warnings = append(c.warnings, deprecationWarning("flag --"+name, flags[name].options.deprecation))
 		}
+	}
+}
+
+func deprecationWarning(name string, message string) string {
+	if message == "" {
+		return name + " is deprecated"
+	}
+
+	return name + " is deprecated: " + message
+}
+
+// groupCommandError returns error for command that only groups subcommands and was called with args starting at
+// argIdx.  Help screen is shown when there are no args, and anything else than a known subcommand is invalid.
+func (c *Broccli) groupCommandError(cmd *Command, args []string, argIdx int) error {
+	if len(args) <= argIdx {
+		return ErrHelp
+	}
 
-		return c.commands[commandName].handler(ctx, c)
+	nameIdx, err := skipFlags(c.commandFlags(cmd), args, argIdx)
+	if err != nil {
+		return err
+	}
+
+	if nameIdx >= len(args) {
+		return errCommandMissingIn(cmd.fullName())
 	}
 
-	// command not found
-	c.printInvalidCommand(os.Args[1])
+	if args[nameIdx] == "-h" || args[nameIdx] == "--help" {
+		return ErrHelp
+	}
 
-	return 1
+	return errCommandInvalidWithName(args[nameIdx], cmd.commands)
 }
 
-func (c *Broccli) sortedCommands() []string {
-	commandNames := reflect.ValueOf(c.commands).MapKeys()
+// skipGlobalFlags returns position of the command name in args, which is after the global flags passed before it.
+// Error is returned when one of the flags is unknown or misses its value.
+func (c *Broccli) skipGlobalFlags(args []string) (int, error) {
+	flags := map[string]*param{}
+
+	for name, flag := range c.global.flags {
+		flags[name] = flag
+	}
+
+	if c.configFlag != nil {
+		flags[c.configFlag.name] = c.configFlag
+	}
+
+	return skipFlags(flags, args, 1)
+}
+
+// commandFlags returns flags that can be passed to the command, ie. its own, inherited and global ones, and the config
+// file flag unless command has a flag with the same name.
+func (c *Broccli) commandFlags(cmd *Command) map[string]*param {
+	flags := cmd.allFlags()
+	if c.configFlag != nil {
+		if _, exists := flags[c.configFlag.name]; !exists {
+			flags[c.configFlag.name] = c.configFlag
+		}
+	}
+
+	return flags
+}
+
+// skipFlags returns position of the first arg, starting from start, that is not one of the flags or a value of such
+// flag.  Flags are recognized the same way as by the tokenizer, so bundled short flags and glued values are skipped.
+// When a flag is unknown or misses its value, its position is returned along with the error.
+func skipFlags(flags map[string]*param, args []string, start int) (int, error) {
+	line := newCommandLine()
+	aliases := flagAliases(flags)
+
+	for i := start; i < len(args); i++ {
+		if !isFlag(args[i], aliases) || args[i] == "-h" || args[i] == "--help" {
+			return i, nil
+		}
+
+		last, err := line.addFlag(flags, aliases, args, i)
+		if err != nil {
+			return i, err
+		}
+
+		i = last
+	}
+
+	return len(args), nil
+}
+
+// handleError prints error with the help screen of the command, or the main help screen when cmd is nil.  Invalid
+// command is printed with a hint about --help instead.  It returns exit code.
+func (c *Broccli) handleError(cmd *Command, err error) int {
+	if !errors.Is(err, ErrHelp) {
+		c.printError(err)
+	}
+
+	// invalid command is most likely a typo, so a suggestion is shown instead of the whole help screen
+	if errors.Is(err, ErrCommandInvalid) {
+		helpCommand := c.programName
+		if cmd != nil {
+			helpCommand += " " + cmd.fullName()
+		}
+
+		fmt.Fprintf(c.options.stderr, "Run '%s --help' for usage.\n", helpCommand)
+
+		return c.exitCode(err)
+	}
+
+	if cmd != nil {
+		c.printCommandHelp(cmd)
+	} else {
+		if !errors.Is(err, ErrHelp) {
+			fmt.Fprintf(c.options.stdout, "\n")
+		}
+
+		c.printHelp()
+	}
+
+	return c.exitCode(err)
+}
+
+// printError prints error to stderr.  Errors joined with errors.Join are printed as a list.
+func (c *Broccli) printError(err error) {
+	errs := flattenErrors(err)
+	if len(errs) == 1 {
+		fmt.Fprintf(c.options.stderr, "ERROR: %s\n", errs[0].Error())
+
+		return
+	}
+
+	fmt.Fprintf(c.options.stderr, "ERROR: %d validation errors:\n", len(errs))
+
+	for _, e := range errs {
+		fmt.Fprintf(c.options.stderr, "  - %s\n", e.Error())
+	}
+}
+
+// flattenErrors returns list of errors that were joined with errors.Join, including the nested ones.
+func flattenErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint
+	if !ok {
+		return []error{err}
+	}
+
+	errs := []error{}
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, flattenErrors(e)...)
+	}
 
-	commandNamesSorted := make([]string, len(commandNames))
+	return errs
+}
 
-	for i, cmd := range commandNames {
-		commandNamesSorted[i] = cmd.String()
+// exitCode returns exit code for the error returned from Parse.
+func (c *Broccli) exitCode(err error) int {
+	if errors.Is(err, ErrHelp) {
+		return 0
 	}
 
-	sort.Strings(commandNamesSorted)
+	return c.options.exitCode(err)
+}
 
-	return commandNamesSorted
+func (c *Broccli) sortedCommands() []string {
+	return sortedVisibleCommands(c.commands)
 }
 
 func (c *Broccli) sortedEnv() []string {
@@ -183,7 +492,7 @@ func (c *Broccli) printHelp() {
 		c.name,
 		c.author,
 		c.usage,
-		path.Base(os.Args[0]),
+		c.programName,
 	)
 
 	if len(c.env) > 0 {
@@ -200,7 +509,7 @@ func (c *Broccli) printHelp() {
 		)
 
 		for _, n := range c.sortedEnv() {
-			_, _ = fmt.Fprintf(tabFormatter, "%s\t%s\n", n, c.env[n].usage)
+			_, _ = fmt.Fprintf(tabFormatter, "%s\t%s\n", n, c.env[n].description())
 		}
 
 		_ = tabFormatter.Flush()
@@ -219,253 +528,439 @@ func (c *Broccli) printHelp() {
 	)
 
 	for _, commandName := range c.sortedCommands() {
-		_, _ = fmt.Fprintf(&helpMessage, "  %s\t%s\n", commandName, c.commands[commandName].usage)
+		_, _ = fmt.Fprint(&helpMessage, c.commands[commandName].helpLine())
 	}
 
 	_ = tabFormatter.Flush()
 
+	_, _ = fmt.Fprint(&helpMessage, c.globalFlagsHelp())
+
 	_, _ = fmt.Fprintf(
 		&helpMessage,
 		"\nRun '%s COMMAND --help' for command syntax.\n",
-		path.Base(os.Args[0]),
+		c.programName,
 	)
 
-	_, err := fmt.Fprint(os.Stdout, helpMessage.String())
+	_, err := fmt.Fprint(c.options.stdout, helpMessage.String())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Unable to build help message")
+		fmt.Fprintf(c.options.stderr, "ERROR: Unable to build help message")
 	}
 }
 
-func (c *Broccli) printInvalidCommand(cmd string) {
-	fmt.Fprintf(os.Stderr, "Invalid command: %s\n\n", cmd)
-	c.printHelp()
-}
+// globalFlagsHelp returns help for flags that are available in every command.
+func (c *Broccli) globalFlagsHelp() string {
+	globalFlags := c.globalFlags()
+	if len(globalFlags) == 0 {
+		return ""
+	}
+
+	var helpMessage strings.Builder
 
-// getFlagSetPtrs creates flagset instance, parses flags and returns list of pointers to results of parsing the flags.
-func (c *Broccli) getFlagSetPtrs(
-	cmd *Command,
-) (map[string]interface{}, map[string]interface{}, []string) {
-	fset := flag.NewFlagSet("flagset", flag.ContinueOnError)
-	// nothing should come out of flagset
-	fset.Usage = func() {}
-	fset.SetOutput(io.Discard)
+	tabFormatter := new(tabwriter.Writer)
+	tabFormatter.Init(
+		&helpMessage,
+		tabWriterMinWidth,
+		tabWriterTabWidth,
+		tabWriterPadding,
+		tabWriterPadChar,
+		0,
+	)
 
-	flagNamePtrs := make(map[string]interface{})
-	flagAliasPtrs := make(map[string]interface{})
+	_, _ = fmt.Fprintf(tabFormatter, "\nGlobal flags: \n")
 
-	flagNamesSorted := cmd.sortedFlags()
-	for _, flagName := range flagNamesSorted {
-		flagInstance := cmd.flags[flagName]
-		if flagInstance.valueType == TypeBool {
-			flagNamePtrs[flagName] = fset.Bool(flagName, false, "")
-			if flagInstance.alias != "" {
-				flagAliasPtrs[flagInstance.alias] = fset.Bool(flagInstance.alias, false, "")
-			}
-		} else {
-			flagNamePtrs[flagName] = fset.String(flagName, "", "")
-			if flagInstance.alias != "" {
-				flagAliasPtrs[flagInstance.alias] = fset.String(flagInstance.alias, "", "")
-			}
-		}
+	for _, flag := range globalFlags {
+		_, _ = fmt.Fprintf(tabFormatter, "%s", flag.helpLine())
 	}
 
-	err := fset.Parse(os.Args[2:])
+	_ = tabFormatter.Flush()
+
+	return helpMessage.String()
+}
+
+func (c *Broccli) printCommandHelp(cmd *Command) {
+	_, err := fmt.Fprint(c.options.stdout, cmd.helpMessage(c.programName)+c.globalFlagsHelp())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Unable to parse flags: %s", err.Error())
+		fmt.Fprintf(c.options.stderr, "ERROR: Unable to build help message")
 	}
+}
 
-	return flagNamePtrs, flagAliasPtrs, fset.Args()
+func (c *Broccli) getenv(name string) string {
+	value, _ := c.options.lookupEnv(name)
+
+	return value
 }
 
-func (c *Broccli) checkEnv(cmd *Command) int {
-	if len(cmd.env) == 0 {
-		return 0
+// stopOnError returns true when validation should stop at err.  When all the errors are collected, err is added to
+// errs and false is returned.
+func (c *Broccli) stopOnError(errs *[]error, err error) bool {
+	if !c.options.allErrors {
+		return true
 	}
 
-	for envName, envVar := range cmd.env {
-		envValue := os.Getenv(envName)
+	*errs = append(*errs, err)
+
+	return false
+}
+
+func (c *Broccli) checkEnv(env map[string]*param, envNamesSorted []string) error {
+	errs := []error{}
+
+	for _, envName := range envNamesSorted {
+		envVar := env[envName]
+		envValue := envVar.valueOrDefault(c.getenv(envName))
 		envVar.flags |= IsRequired
 
 		err := envVar.validateValue(envValue)
 		if err != nil {
-			fmt.Fprintf(
-				os.Stderr,
-				"ERROR: %s %s: %s\n",
-				c.getParamTypeName(ParamEnvVar),
-				envVar.name,
-				err.Error(),
-			)
-			cmd.printHelp()
+			err = &ParamError{Kind: ParamEnvVar, Name: envName, Value: envVar.displayValue(envValue), Err: err}
+			if c.stopOnError(&errs, err) {
+				return err
+			}
 
-			return 1
+			continue
 		}
+
+		c.parsedEnv[envName] = envVar.canonicalValue(envValue)
 	}
 
-	return 0
+	return errors.Join(errs...)
 }
 
-func (c *Broccli) processOnTrue(
-	cmd *Command,
-	flagNames []string,
-	nflags map[string]interface{},
-	aflags map[string]interface{},
-) {
+func (c *Broccli) processOnTrue(cmd *Command, flagNames []string, line *commandLine) {
+	flags := cmd.allFlags()
+
 	for _, name := range flagNames {
-		if cmd.flags[name].valueType != TypeBool {
+		if flags[name].valueType != TypeBool {
 			continue
 		}
 
-		if cmd.flags[name].options.onTrue == nil {
+		if flags[name].options.onTrue == nil {
 			continue
 		}
 
 		// OnTrue is called when a flag is true
-		//nolint:forcetypeassert
-		if *(nflags[name]).(*bool) || *(aflags[cmd.flags[name].alias]).(*bool) {
-			cmd.flags[name].options.onTrue(cmd)
+		flagValue, _ := c.flagValueWithFallback(flags[name], line.value(flags[name]))
+		if isTrue, _ := strconv.ParseBool(flagValue); isTrue {
+			flags[name].options.onTrue(cmd)
 		}
 	}
 }
 
-func (c *Broccli) processFlags(
-	cmd *Command,
-	flagNames []string,
-	nflags map[string]interface{},
-	aflags map[string]interface{},
-) int {
+func (c *Broccli) processFlags(cmd *Command, flagNames []string, line *commandLine) error {
+	flags := cmd.allFlags()
+	errs := []error{}
+
 	for _, name := range flagNames {
-		flag := cmd.flags[name]
+		flag := flags[name]
 
-		if flag.valueType == TypeBool {
-			c.parsedFlags[name] = "false"
-			//nolint:forcetypeassert
-			if *(nflags[name]).(*bool) || (cmd.flags[name].alias != "" && *(aflags[cmd.flags[name].alias]).(*bool)) {
-				c.parsedFlags[name] = "true"
+		// error has already been returned by the tokenizer
+		if line.failedNames[name] {
+			continue
+		}
+
+		if flag.valueType != TypeBool && flag.flags&AllowRepeated > 0 {
+			err := c.processRepeatedFlag(flag, line.flagValues[name])
+			if err != nil && c.stopOnError(&errs, err) {
+				return err
 			}
 
 			continue
 		}
 
-		//nolint:forcetypeassert
-		aliasValue := ""
-		if flag.alias != "" {
-			aliasValue = *(aflags[flag.alias]).(*string)
+		if line.passedNames[name] && line.passedAliases[name] {
+			err := &ParamError{Kind: ParamFlag, Name: name, Err: errFlagConflictWithNames(flag.alias, flag.name)}
+			if c.stopOnError(&errs, err) {
+				return err
+			}
+
+			continue
 		}
-		//nolint:forcetypeassert
-		nameValue := *(nflags[name]).(*string)
 
-		if nameValue != "" && aliasValue != "" {
-			fmt.Fprintf(os.Stderr, "ERROR: Both -%s and --%s passed", flag.alias, flag.name)
+		flagValue := line.value(flag)
+		if flagValue == "" && flag.fileFlag != nil {
+			var err error
+
+			flagValue, err = c.readFileFlag(flag, line)
+			if err != nil {
+				if c.stopOnError(&errs, err) {
+					return err
+				}
+
+				continue
+			}
+		}
 
-			return 1
+		flagValue, source := c.flagValueWithFallback(flag, flagValue)
+		if flagValue == "" && flag.flags&IsRequired > 0 && c.canPrompt() {
+			flagValue = c.promptValue(flag, "--"+name)
 		}
 
-		flagValue := aliasValue
-		if nameValue != "" {
-			flagValue = nameValue
+		if flag.valueType == TypeBool && flagValue == "" {
+			flagValue = "false"
 		}
 
 		err := flag.validateValue(flagValue)
 		if err != nil {
-			fmt.Fprintf(
-				os.Stderr,
-				"ERROR: %s %s: %s\n",
-				c.getParamTypeName(ParamFlag),
-				name,
-				err.Error(),
-			)
-			cmd.printHelp()
+			err = &ParamError{Kind: ParamFlag, Name: name, Value: flag.displayValue(flagValue), Source: source, Err: err}
+			if c.stopOnError(&errs, err) {
+				return err
+			}
+
+			continue
+		}
+
+		if flag.valueType == TypeBool {
+			isTrue, _ := strconv.ParseBool(flagValue)
+			flagValue = strconv.FormatBool(isTrue)
+		}
+
+		c.parsedFlags[name] = flag.canonicalValue(flagValue)
+		c.passedFlags[name] = source != sourceDefaultValue && flagValue != "" && flagValue != "false"
+	}
+
+	return errors.Join(errs...)
+}
+
+// processRepeatedFlag validates each occurrence of flag with AllowRepeated.  When flag was not passed, values from
+// the config file are treated as occurrences, and values from other sources as a single occurrence.
+func (c *Broccli) processRepeatedFlag(flag *param, occurrences []string) error {
+	source := ""
+
+	if len(occurrences) == 0 {
+		var value string
+
+		value, source = c.flagValueWithFallback(flag, "")
+		if configValue, ok := c.configValues[flag.name]; ok && strings.HasPrefix(source, "config file") {
+			occurrences = configValue.values
+		} else if value != "" {
+			occurrences = []string{value}
+		} else if flag.flags&IsRequired > 0 && c.canPrompt() {
+			if value = c.promptValue(flag, "--"+flag.name); value != "" {
+				occurrences = []string{value}
+			}
+		}
+	}
+
+	if len(occurrences) == 0 {
+		err := flag.validateValue("")
+		if err != nil {
+			return &ParamError{Kind: ParamFlag, Name: flag.name, Err: err}
+		}
+	}
+
+	canonical := make([]string, len(occurrences))
+
+	for i, occurrence := range occurrences {
+		err := flag.validateValue(occurrence)
+		if err != nil {
+			return &ParamError{
+				Kind:   ParamFlag,
+				Name:   flag.name,
+				Value:  flag.displayValue(occurrence),
+				Source: source,
+				Err:    err,
+			}
+		}
+
+		canonical[i] = flag.canonicalValue(occurrence)
+	}
+
+	occurrences = canonical
+
+	c.parsedFlags[flag.name] = strings.Join(occurrences, flag.separator())
+	c.parsedRepeated[flag.name] = occurrences
+	c.passedFlags[flag.name] = source != sourceDefaultValue && len(occurrences) > 0
 
-			return 1
+	return nil
+}
+
+// flagValueWithFallback returns value of flag taken from the first source that has it, in the following order:
+// command line, environment variables bound to the flag, config file, default value.  Second returned value describes
+// the source and is meant to be added to error messages.  It is empty when value comes from the command line.
+func (c *Broccli) flagValueWithFallback(flag *param, value string) (string, string) {
+	if value != "" {
+		return value, ""
+	}
+
+	for _, envName := range flag.options.envVars {
+		envValue := c.getenv(envName)
+		if envValue != "" {
+			return envValue, "env var " + envName
 		}
+	}
+
+	if configValue, ok := c.configValues[flag.name]; ok && flag != c.configFlag {
+		return strings.Join(configValue.values, flag.separator()),
+			fmt.Sprintf("config file %s key %s", c.configPath, configValue.key)
+	}
 
-		c.parsedFlags[name] = flagValue
+	if flag.options.defaultValue != "" {
+		return flag.options.defaultValue, sourceDefaultValue
 	}
 
-	return 0
+	return "", ""
 }
 
-func (c *Broccli) processArgs(cmd *Command, argNamesSorted []string, args []string) int {
+func (c *Broccli) processArgs(cmd *Command, argNamesSorted []string, args []string) error {
+	errs := []error{}
+
 	for argIdx, argName := range argNamesSorted {
+		arg := cmd.args[argName]
+
+		if arg.options.variadic {
+			rest := []string{}
+			if len(args) > argIdx {
+				rest = args[argIdx:]
+			}
+
+			err := c.processVariadicArg(arg, rest)
+			if err != nil && c.stopOnError(&errs, err) {
+				return err
+			}
+
+			continue
+		}
+
 		argValue := ""
 		if len(args) >= argIdx+1 {
 			argValue = args[argIdx]
 		}
 
-		err := cmd.args[argName].validateValue(argValue)
+		source := ""
+		if argValue == "" && arg.options.defaultValue != "" {
+			argValue = arg.options.defaultValue
+			source = sourceDefaultValue
+		}
+
+		if argValue == "" && arg.flags&IsRequired > 0 && c.canPrompt() {
+			argValue = c.promptValue(arg, arg.valuePlaceholder)
+		}
+
+		err := arg.validateValue(argValue)
 		if err != nil {
-			fmt.Fprintf(
-				os.Stderr,
-				"ERROR: %s %s: %s\n",
-				c.getParamTypeName(ParamArg),
-				cmd.args[argName].valuePlaceholder,
-				err.Error(),
-			)
-			cmd.printHelp()
+			err = &ParamError{
+				Kind:   ParamArg,
+				Name:   argName,
+				Value:  arg.displayValue(argValue),
+				Source: source,
+				Err:    err,
+				label:  arg.valuePlaceholder,
+			}
+			if c.stopOnError(&errs, err) {
+				return err
+			}
 
-			return 1
+			continue
 		}
 
-		c.parsedArgs[argName] = argValue
+		c.parsedArgs[argName] = arg.canonicalValue(argValue)
+	}
+
+	// only variadic arg can take more values than there are args
+	if cmd.variadicArg() == nil && len(args) > len(argNamesSorted) {
+		err := errArgUnexpectedWithValue(args[len(argNamesSorted)])
+		if c.stopOnError(&errs, err) {
+			return err
+		}
 	}
 
-	return 0
+	return errors.Join(errs...)
 }
 
-func (c *Broccli) processOnPostValidation(cmd *Command) int {
-	if cmd.options.onPostValidation == nil {
-		return 0
+// processVariadicArg validates number of values of variadic arg and each of its values.
+func (c *Broccli) processVariadicArg(arg *param, values []string) error {
+	source := ""
+	if len(values) == 0 && arg.options.defaultValue != "" {
+		values = []string{arg.options.defaultValue}
+		source = sourceDefaultValue
 	}
 
-	err := cmd.options.onPostValidation(cmd)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
-		cmd.printHelp()
+	paramErr := &ParamError{Kind: ParamArg, Name: arg.name, Source: source, label: arg.argPlaceholder()}
+
+	minCount := arg.minArgCount()
+	if len(values) == 0 && minCount > 0 {
+		paramErr.Err = ErrParamValueMissing
+
+		return paramErr
+	}
+
+	if len(values) < minCount || (arg.options.maxCount > 0 && len(values) > arg.options.maxCount) {
+		paramErr.Err = errArgCountInvalidWithRange(len(values), minCount, arg.options.maxCount)
 
-		return 1
+		return paramErr
 	}
 
-	return 0
+	canonical := make([]string, len(values))
+
+	for i, value := range values {
+		err := arg.validateValue(value)
+		if err != nil {
+			paramErr.Value = arg.displayValue(value)
+			paramErr.Err = err
+
+			return paramErr
+		}
+
+		canonical[i] = arg.canonicalValue(value)
+	}
+
+	values = canonical
+
+	c.parsedArgs[arg.name] = strings.Join(values, arg.separator())
+	c.parsedVariadic[arg.name] = values
+
+	return nil
 }
 
-func (c *Broccli) parseFlags(cmd *Command) int {
+func (c *Broccli) parseFlags(cmd *Command, args []string) error {
+	errs := []error{}
+
 	// check required environment variables
-	if exitCode := c.checkEnv(cmd); exitCode != 0 {
-		return exitCode
+	err := c.checkEnv(cmd.env, cmd.sortedEnv())
+	if err != nil && c.stopOnError(&errs, err) {
+		return err
+	}
+
+	line, err := c.tokenize(c.commandFlags(cmd), args)
+	if err != nil && c.stopOnError(&errs, err) {
+		return err
 	}
 
-	flags := cmd.sortedFlags()
-	flagNamePtrs, flagAliasPtrs, args := c.getFlagSetPtrs(cmd)
+	// load values from the config file so that they can be used as a fallback for flags
+	err = c.loadConfig(cmd, line)
+	if err != nil && c.stopOnError(&errs, err) {
+		return err
+	}
 
 	// Loop through boolean flags and execute onTrue() hook if exists.  That function might be used to change behaviour
 	// of other flags, eg. when -e is added, another flag or argument might become required (or obsolete).
-	// Bool fields will be parsed out in this loop so no reason to process them again in the next one.
-	c.processOnTrue(cmd, flags, flagNamePtrs, flagAliasPtrs)
+	c.processOnTrue(cmd, cmd.sortedAllFlags(), line)
 
-	if exitCode := c.processFlags(cmd, flags, flagNamePtrs, flagAliasPtrs); exitCode != 0 {
-		return exitCode
+	err = c.processFlags(cmd, cmd.sortedAllFlags(), line)
+	if err != nil && c.stopOnError(&errs, err) {
+		return err
 	}
 
-	argsNamesSorted := cmd.sortedArgs()
-	if exitCode := c.processArgs(cmd, argsNamesSorted, args); exitCode != 0 {
-		return exitCode
+	err = c.processArgs(cmd, cmd.sortedArgs(), line.args)
+	if err != nil && c.stopOnError(&errs, err) {
+		return err
 	}
 
-	if exitCode := c.processOnPostValidation(cmd); exitCode != 0 {
-		return exitCode
+	err = c.checkFlagGroups(cmd)
+	if err != nil && c.stopOnError(&errs, err) {
+		return err
 	}
 
-	return 0
-}
-
-func (c *Broccli) getParamTypeName(t int8) string {
-	if t == ParamArg {
-		return "Argument"
+	// post validation runs only when all the values are valid
+	if len(errs) > 0 {
+		return errors.Join(errs...)
 	}
 
-	if t == ParamEnvVar {
-		return "Env var"
+	if cmd.options.onPostValidation != nil {
+		return cmd.options.onPostValidation(cmd)
 	}
 
-	return "Flag"
+	return nil
 }
+
+const sourceDefaultValue = "default value"
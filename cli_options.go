@@ -0,0 +1,101 @@
+package broccli
+
+import (
+	"io"
+	"os"
+)
+
+type broccliOptions struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	stdin     io.Reader
+	lookupEnv func(key string) (string, bool)
+	allErrors bool
+	exitCode  func(err error) int
+	prompt    bool
+	// clearSensitive removes values of sensitive params after the handler
+	clearSensitive bool
+	// isTerminal checks if input is a terminal, so that prompting does not happen in scripts
+	isTerminal func(r io.Reader) bool
+}
+
+// BroccliOption defines an optional configuration function for the CLI, intended for specific use cases.
+// It should not be created manually; use one of the predefined functions below.
+type BroccliOption func(opts *broccliOptions)
+
+func defaultBroccliOptions() broccliOptions {
+	return broccliOptions{
+		stdout:     os.Stdout,
+		stderr:     os.Stderr,
+		stdin:      os.Stdin,
+		lookupEnv:  os.LookupEnv,
+		exitCode:   defaultExitCode,
+		isTerminal: isTerminal,
+	}
+}
+
+// WithOutput sets writer that help screens and handler output go to.  Default is os.Stdout.
+func WithOutput(w io.Writer) BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.stdout = w
+	}
+}
+
+// WithErrorOutput sets writer that errors go to.  Default is os.Stderr.
+func WithErrorOutput(w io.Writer) BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.stderr = w
+	}
+}
+
+// WithInput sets reader that handlers can read the input from.  Default is os.Stdin.
+func WithInput(r io.Reader) BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.stdin = r
+	}
+}
+
+// WithLookupEnv sets function that is used to get values of environment variables.  Default is os.LookupEnv.
+func WithLookupEnv(fn func(key string) (string, bool)) BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.lookupEnv = fn
+	}
+}
+
+// WithAllErrors makes the validation continue after the first invalid flag, arg or environment variable, so that all
+// the failures are reported at once.  Error returned from Parse is then created with errors.Join.
+func WithAllErrors() BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.allErrors = true
+	}
+}
+
+// WithExitCodeMapper sets function that turns errors into exit codes.  It is used for errors returned from handlers
+// added with CommandFor and for invalid command line.  By default, 1 is returned for every error.
+func WithExitCodeMapper(fn func(err error) int) BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.exitCode = fn
+	}
+}
+
+func defaultExitCode(_ error) int {
+	return 1
+}
+
+// WithPrompt makes the CLI ask for values of missing required flags and args when the input is a terminal.  Answers
+// are validated and the question is repeated until a valid value is entered.  When the input is not a terminal, eg.
+// in scripts, missing values result in an error as usual.  Questions are written to the error output.
+func WithPrompt() BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.prompt = true
+	}
+}
+
+// WithClearSensitive makes Run, RunArgs and Execute remove values of params with Sensitive option once the handler
+// is done, so that they are not available with Flag, Arg or EnvValue anymore.  Struct fields bound to these params are
+// set to zero values.
+func WithClearSensitive() BroccliOption {
+	return func(opts *broccliOptions) {
+		opts.clearSensitive = true
+	}
+}
@@ -1,72 +1,26 @@
 package broccli
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strings"
 	"testing"
 )
 
-func initTestCLI(t *testing.T) (*os.File, *os.File) {
-	tmpFile, err := os.CreateTemp(t.TempDir(), "stdout")
-	if err != nil {
-		t.Error("error creating temporary file")
-
-		return nil, nil
-	}
-
-	devNull, err := os.OpenFile("/dev/null", os.O_APPEND, 0o600)
-	if err != nil {
-		t.Error("error opening temporary file")
+func newTestCLI(opts ...BroccliOption) *Broccli {
+	opts = append([]BroccliOption{WithOutput(io.Discard), WithErrorOutput(io.Discard)}, opts...)
 
-		err := os.Remove(tmpFile.Name())
-		if err != nil {
-			t.Error("error removing temporary file")
-		}
-
-		err = tmpFile.Close()
-		if err != nil {
-			t.Error("error closing temporary file")
-		}
-
-		return nil, nil
-	}
-
-	os.Stdout = devNull
-	os.Stderr = devNull
-
-	return tmpFile, devNull
-}
-
-func removeTestFiles(t *testing.T, tmpFile *os.File, devNull *os.File) {
-	err := os.Remove(tmpFile.Name())
-	if err != nil {
-		t.Error("error removing temporary file")
-	}
-
-	err = tmpFile.Close()
-	if err != nil {
-		t.Error("error closing temporary file")
-	}
-
-	err = devNull.Close()
-	if err != nil {
-		t.Error("error closing /dev/null")
-	}
+	return NewBroccli("Example", "App", "Author <a@example.com>", opts...)
 }
 
 // TestCLIStringFlag tests a CLI instance with single flag instance.
 func TestCLIStringFlag(t *testing.T) {
 	t.Parallel()
 
-	tmpFile, devNull := initTestCLI(t)
-	defer func() {
-		removeTestFiles(t, tmpFile, devNull)
-	}()
-
-	broccli := NewBroccli("Example", "App", "Author <a@example.com>")
+	broccli := newTestCLI()
 	cmd1 := broccli.Command("cmd", "Prints out a string", func(_ context.Context, c *Broccli) int {
 		text := c.Flag("text")
 		if text == "tekst" {
@@ -77,38 +31,32 @@ func TestCLIStringFlag(t *testing.T) {
 	})
 	cmd1.Flag("text", "t", "Text", "Text to check", TypeString, IsRequired)
 
-	os.Args = []string{"test", "wrongcmd"}
-	got := broccli.Run(context.Background())
+	got := broccli.RunArgs(context.Background(), []string{"test", "wrongcmd"})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd"})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd", "-t", "tekst"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd", "-t", "tekst"})
 	if got != 2 {
 		t.Errorf("CLI.Run() should have returned 2 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd", "-t", "return3"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd", "-t", "return3"})
 	if got != 3 {
 		t.Errorf("CLI.Run() should have returned 3 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd", "--text", "tekst"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd", "--text", "tekst"})
 	if got != 2 {
 		t.Errorf("CLI.Run() should have returned 2 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd", "--text", "return3"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd", "--text", "return3"})
 	if got != 3 {
 		t.Errorf("CLI.Run() should have returned 3 instead of %d", got)
 	}
@@ -118,12 +66,7 @@ func TestCLIStringFlag(t *testing.T) {
 func TestCLIStringFlagNoAlias(t *testing.T) {
 	t.Parallel()
 
-	tmpFile, devNull := initTestCLI(t)
-	defer func() {
-		removeTestFiles(t, tmpFile, devNull)
-	}()
-
-	broccli := NewBroccli("Example", "App", "Author <a@example.com>")
+	broccli := newTestCLI()
 	cmd1 := broccli.Command("cmd", "Prints out a string", func(_ context.Context, c *Broccli) int {
 		text := c.Flag("text")
 		text2 := c.Flag("text2")
@@ -139,26 +82,22 @@ func TestCLIStringFlagNoAlias(t *testing.T) {
 	cmd1.Flag("text", "", "Text", "Text to check", TypeString, IsRequired)
 	cmd1.Flag("text2", "", "Text2", "Text2 to check", TypeString, IsRequired)
 
-	os.Args = []string{"test", "wrongcmd"}
-	got := broccli.Run(context.Background())
+	got := broccli.RunArgs(context.Background(), []string{"test", "wrongcmd"})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd"})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd", "--text", "tekst", "--text2", "tekst2"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd", "--text", "tekst", "--text2", "tekst2"})
 	if got != 2 {
 		t.Errorf("CLI.Run() should have returned 2 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd", "--text", "return3", "--text2", "return3"}
-	got = broccli.Run(context.Background())
+	got = broccli.RunArgs(context.Background(), []string{"test", "cmd", "--text", "return3", "--text2", "return3"})
 	if got != 3 {
 		t.Errorf("CLI.Run() should have returned 3 instead of %d", got)
 	}
@@ -168,17 +107,14 @@ func TestCLIStringFlagNoAlias(t *testing.T) {
 func TestCLIVariousFlags(t *testing.T) {
 	t.Parallel()
 
-	tmpFile, devNull := initTestCLI(t)
-	defer func() {
-		removeTestFiles(t, tmpFile, devNull)
-	}()
+	var stdout bytes.Buffer
 
-	c := NewBroccli("Example", "App", "Author <a@example.com>")
+	c := NewBroccli("Example", "App", "Author <a@example.com>", WithOutput(&stdout), WithErrorOutput(io.Discard))
 	cmd1 := c.Command("cmd1", "Prints out a string", func(_ context.Context, c *Broccli) int {
-		_, _ = fmt.Fprintf(tmpFile, "TESTVALUE:%s%s\n\n", c.Flag("tekst"), c.Flag("alphanumdots"))
+		_, _ = fmt.Fprintf(c.Stdout(), "TESTVALUE:%s%s\n\n", c.Flag("tekst"), c.Flag("alphanumdots"))
 
 		if c.Flag("bool") == "true" {
-			_, _ = fmt.Fprintf(tmpFile, "BOOL:true")
+			_, _ = fmt.Fprintf(c.Stdout(), "BOOL:true")
 		}
 
 		return 2
@@ -193,64 +129,355 @@ func TestCLIVariousFlags(t *testing.T) {
 	// Boolean should work fine even when the optional OnTrue is not passed
 	cmd1.Flag("bool", "b", "", "Bool value", TypeBool, 0)
 
-	os.Args = []string{"test", "cmd1"}
-	got := c.Run(context.Background())
+	got := c.RunArgs(context.Background(), []string{"test", "cmd1"})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd1", "-t", ""}
-	got = c.Run(context.Background())
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "-t", ""})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd1", "--tekst", "Tekst123", "--alphanumdots"}
-	got = c.Run(context.Background())
-	if got != 2 {
-		t.Errorf("CLI.Run() should have returned 2 instead of %d", got)
+	// flag that requires a value cannot be the last arg
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "--tekst", "Tekst123", "--alphanumdots"})
+	if got != 1 {
+		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd1", "--tekst", "Tekst123", "-r"}
-	got = c.Run(context.Background())
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "--tekst", "Tekst123", "-r"})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd1", "--tekst", "Tekst123", "--alphanumdots", "aZ0-9"}
-	got = c.Run(context.Background())
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "--tekst", "Tekst123", "--alphanumdots", "aZ0-9"})
 	if got != 1 {
 		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
 	}
 
-	os.Args = []string{"test", "cmd1", "--tekst", "Tekst123", "--alphanumdots", "aZ0.9", "-b"}
-	got = c.Run(context.Background())
+	got = c.RunArgs(
+		context.Background(),
+		[]string{"test", "cmd1", "--tekst", "Tekst123", "--alphanumdots", "aZ0.9", "-b"},
+	)
 	if got != 2 {
 		t.Errorf("CLI.Run() should have returned 2 instead of %d", got)
 	}
 
-	f2, err := os.Open(tmpFile.Name())
-	if err != nil {
-		t.Error("error opening temporary file")
+	if !strings.Contains(stdout.String(), "TESTVALUE:Tekst123aZ0.9") {
+		t.Errorf("Cmd handler failed to work")
+	}
+
+	if !strings.Contains(stdout.String(), "BOOL:true") {
+		t.Errorf("Cmd handler failed to work")
+	}
+}
+
+// TestCLISubcommands tests a CLI with nested commands and flags inherited from parent commands.
+func TestCLISubcommands(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	cluster := c.Command("cluster", "Manages clusters", nil)
+	cluster.Flag("cluster-name", "c", "NAME", "Name of the cluster", TypeAlphanumeric, IsRequired|AllowHyphen)
+	cluster.Flag("quiet", "q", "", "Quiet output", TypeBool, 0)
+	cluster.Flag("verbose", "V", "", "Verbose output", TypeBool, 0)
+
+	node := cluster.Command("node", "Manages nodes", func(_ context.Context, _ *Broccli) int {
+		return 4
+	})
+	add := node.Command("add", "Adds a node", func(_ context.Context, c *Broccli) int {
+		if c.Flag("cluster-name") == "main" && c.Arg("node") == "node1" {
+			return 2
+		}
+
+		return 3
+	})
+	add.Arg("node", "NODE", "Name of the node", TypeAlphanumeric, IsRequired)
+
+	testCases := []struct {
+		args     []string
+		exitCode int
+	}{
+		{[]string{"test", "cluster"}, 0},
+		{[]string{"test", "cluster", "--help"}, 0},
+		{[]string{"test", "cluster", "wrong"}, 1},
+		{[]string{"test", "cluster", "node", "-c", "main"}, 4},
+		{[]string{"test", "cluster", "node", "add", "--help"}, 0},
+		{[]string{"test", "cluster", "node", "add", "node1"}, 1},
+		{[]string{"test", "cluster", "node", "add", "-c", "main"}, 1},
+		{[]string{"test", "cluster", "node", "add", "-c", "main", "node1"}, 2},
+		{[]string{"test", "cluster", "node", "add", "--cluster-name", "other", "node1"}, 3},
+		{[]string{"test", "cluster", "-c", "main", "node"}, 4},
+		{[]string{"test", "cluster", "-c", "main", "node", "add", "node1"}, 2},
+		{[]string{"test", "cluster", "node", "--cluster-name=main", "add", "node1"}, 2},
+		{[]string{"test", "cluster", "-c", "main"}, 1},
+		{[]string{"test", "cluster", "-c", "main", "wrong"}, 1},
+		{[]string{"test", "cluster", "--unknown", "node"}, 1},
+		{[]string{"test", "cluster", "-c"}, 1},
+		{[]string{"test", "cluster", "-c", "main", "--help"}, 0},
+		{[]string{"test", "cluster", "-cmain", "node", "add", "node1"}, 2},
+		{[]string{"test", "cluster", "-qVc", "main", "node", "add", "node1"}, 2},
+		{[]string{"test", "cluster", "-qV", "node", "-c", "main"}, 4},
+	}
+
+	for _, testCase := range testCases {
+		got := c.RunArgs(context.Background(), testCase.args)
+		if got != testCase.exitCode {
+			t.Errorf("CLI.Run() with %v should have returned %d instead of %d", testCase.args, testCase.exitCode, got)
+		}
+	}
+
+	_, err := c.Parse([]string{"test", "cluster", "--cluster-nme", "main", "node"})
+	if !errors.Is(err, ErrFlagUnknown) || !strings.Contains(err.Error(), "did you mean --cluster-name?") {
+		t.Errorf("Parse() should have returned error for unknown flag before subcommand: %v", err)
+	}
+}
+
+// TestCLIOutputAndEnv tests that help screen and errors go to configured writers and env vars are read using
+// the configured lookup function.
+func TestCLIOutputAndEnv(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	env := map[string]string{}
+	c := NewBroccli("Example", "App", "Author <a@example.com>",
+		WithOutput(&stdout),
+		WithErrorOutput(&stderr),
+		WithLookupEnv(func(key string) (string, bool) {
+			value, ok := env[key]
+
+			return value, ok
+		}),
+	)
+	cmd1 := c.Command("cmd1", "Does nothing", func(_ context.Context, _ *Broccli) int {
+		return 2
+	})
+	cmd1.Env("LEVEL", "Level", TypeInt, IsRequired)
+
+	got := c.RunArgs(context.Background(), []string{"/usr/bin/test", "--help"})
+	if got != 0 || !strings.Contains(stdout.String(), "Usage: test COMMAND") {
+		t.Errorf("CLI.RunArgs() should have printed help to the configured output")
+	}
+
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1"})
+	if got != 1 || !strings.Contains(stderr.String(), "ERROR: Env var LEVEL") {
+		t.Errorf("CLI.RunArgs() should have printed error to the configured error output")
+	}
+
+	env["LEVEL"] = "5"
+
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1"})
+	if got != 2 {
+		t.Errorf("CLI.RunArgs() should have returned 2 instead of %d", got)
+	}
+}
+
+// TestCLIDefaults tests default values of flags, args and env vars.
+func TestCLIDefaults(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	cmd1 := c.Command("cmd1", "Checks defaults", func(_ context.Context, c *Broccli) int {
+		if c.Flag("format") != "json" || c.Flag("color") != "true" || c.Arg("count") != "10" ||
+			c.EnvValue("LEVEL") != "3" {
+			return 3
+		}
+
+		return 2
+	})
+	cmd1.Flag("format", "f", "FORMAT", "Output format", TypeAlphanumeric, IsRequired, WithDefault("json"))
+	cmd1.Flag("color", "", "", "Use colors", TypeBool, 0, WithDefault("true"))
+	cmd1.Arg("count", "COUNT", "Count", TypeInt, 0, WithDefault("10"))
+	cmd1.Env("LEVEL", "Level", TypeInt, IsRequired, WithDefault("3"))
+
+	got := c.RunArgs(context.Background(), []string{"test", "cmd1"})
+	if got != 2 {
+		t.Errorf("CLI.RunArgs() should have returned 2 instead of %d", got)
 	}
 
-	defer func() {
-		err := f2.Close()
-		if err != nil {
-			t.Error("error closing temporary file")
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "-f", "yaml"})
+	if got != 3 {
+		t.Errorf("CLI.RunArgs() should have returned 3 instead of %d", got)
+	}
+}
+
+// TestCLIFlagsFromEnv tests flags that fall back to environment variables.
+func TestCLIFlagsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+
+	env := map[string]string{"APP_TOKEN": "secret", "APP_VERBOSE": "1"}
+	c := newTestCLI(
+		WithErrorOutput(&stderr),
+		WithLookupEnv(func(key string) (string, bool) {
+			value, ok := env[key]
+
+			return value, ok
+		}),
+	)
+	cmd1 := c.Command("cmd1", "Checks env vars", func(_ context.Context, c *Broccli) int {
+		if c.Flag("token") != "secret" || c.Flag("verbose") != "true" || c.Flag("level") != "2" {
+			return 3
 		}
-	}()
 
-	b, err := io.ReadAll(f2)
+		return 2
+	})
+	cmd1.Flag("token", "t", "TOKEN", "Token", TypeString, IsRequired, FromEnv("APP_TOKEN_OLD", "APP_TOKEN"))
+	cmd1.Flag("verbose", "v", "", "Verbose", TypeBool, 0, FromEnv("APP_VERBOSE"))
+	cmd1.Flag("level", "l", "LEVEL", "Level", TypeInt, 0, FromEnv("APP_LEVEL"), WithDefault("2"))
+
+	got := c.RunArgs(context.Background(), []string{"test", "cmd1"})
+	if got != 2 {
+		t.Errorf("CLI.RunArgs() should have returned 2 instead of %d", got)
+	}
+
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "-t", "other"})
+	if got != 3 {
+		t.Errorf("CLI.RunArgs() should have returned 3 instead of %d", got)
+	}
+
+	// negated flag takes precedence over the env var
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "--no-verbose"})
+	if got != 3 {
+		t.Errorf("CLI.RunArgs() should have returned 3 instead of %d", got)
+	}
+
+	env["APP_LEVEL"] = "x"
+
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1"})
+	if got != 1 || !strings.Contains(stderr.String(), "Flag level (from env var APP_LEVEL)") {
+		t.Errorf("CLI.RunArgs() should have failed on invalid env var value")
+	}
+}
+
+// TestCLIVariadicArgs tests variadic args and unexpected args.
+func TestCLIVariadicArgs(t *testing.T) {
+	t.Parallel()
+
+	broccli := newTestCLI()
+	cmd1 := broccli.Command("copy", "Copies files", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd1.Arg("dest", "DEST", "Destination", TypeAlphanumeric, IsRequired)
+	cmd1.Arg("sizes", "SIZES", "Sizes", TypeInt, IsRequired, Variadic(0, 3))
+
+	cmd2 := broccli.Command("print", "Prints text", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd2.Arg("text", "TEXT", "Text", TypeString, 0)
+
+	_, err := broccli.Parse([]string{"test", "copy", "dir", "1", "22", "333"})
 	if err != nil {
-		t.Error("error reading output file contents")
+		t.Fatalf("Parse() returned error: %s", err.Error())
 	}
 
-	if !strings.Contains(string(b), "TESTVALUE:Tekst123aZ0.9") {
-		t.Errorf("Cmd handler failed to work")
+	sizes, err := broccli.ArgInts("sizes")
+	if err != nil || len(sizes) != 3 || sizes[2] != 333 || broccli.Arg("dest") != "dir" {
+		t.Errorf("Parse() set invalid values: %v %s", sizes, broccli.Arg("dest"))
 	}
 
-	if !strings.Contains(string(b), "BOOL:true") {
-		t.Errorf("Cmd handler failed to work")
+	testCases := []struct {
+		args []string
+		err  error
+	}{
+		{[]string{"test", "copy", "dir"}, ErrParamValueMissing},
+		{[]string{"test", "copy", "dir", "1", "2", "3", "4"}, ErrArgCountInvalid},
+		{[]string{"test", "copy", "dir", "1", "x"}, ErrParamValueInvalid},
+		{[]string{"test", "print", "a", "b"}, ErrArgUnexpected},
+	}
+
+	for _, testCase := range testCases {
+		_, err = broccli.Parse(testCase.args)
+		if !errors.Is(err, testCase.err) {
+			t.Errorf("Parse() with %v should have returned %v instead of %v", testCase.args, testCase.err, err)
+		}
+	}
+}
+
+// TestCLIChoices tests flags and args with choices.
+func TestCLIChoices(t *testing.T) {
+	t.Parallel()
+
+	broccli := newTestCLI()
+	cmd1 := broccli.Command("print", "Prints", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd1.Flag("output", "o", "FORMAT", "Output format", TypeString, 0, OneOf("json", "yaml"), IgnoreCase())
+	cmd1.Arg("level", "LEVEL", "Level", TypeInt, 0, OneOf("1", "2", "3"))
+
+	_, err := broccli.Parse([]string{"test", "print", "-o", "JSON", "2"})
+	if err != nil || broccli.Flag("output") != "json" || broccli.Arg("level") != "2" {
+		t.Errorf("Parse() should have accepted the choices: %v", err)
+	}
+
+	_, err = broccli.Parse([]string{"test", "print", "4"})
+	if err == nil || err.Error() != "Argument LEVEL: param value invalid: must be one of 1, 2, 3" {
+		t.Errorf("Parse() should have returned error for invalid choice instead of %v", err)
+	}
+}
+
+// TestCLIGlobalFlags tests flags available in every command.
+func TestCLIGlobalFlags(t *testing.T) {
+	t.Parallel()
+
+	var stdout bytes.Buffer
+
+	broccli := newTestCLI(WithOutput(&stdout))
+	broccli.GlobalFlag("verbose", "v", "", "Verbose output", TypeBool, 0)
+	broccli.GlobalFlag("output", "o", "FORMAT", "Output format", TypeString, 0, OneOf("json", "text"))
+
+	cluster := broccli.Command("cluster", "Manages clusters", nil)
+	cluster.Command("list", "Lists clusters", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+
+	cmd := broccli.Command("print", "Prints", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd.Flag("output", "", "PATH", "Output file", TypeString, 0)
+
+	testCases := []struct {
+		args    []string
+		verbose string
+		output  string
+	}{
+		{[]string{"test", "-v", "--output", "json", "cluster", "list"}, "true", "json"},
+		{[]string{"test", "cluster", "list", "-o=text", "--verbose"}, "true", "text"},
+		{[]string{"test", "--output=json", "cluster", "list", "--no-verbose"}, "false", "json"},
+		{[]string{"test", "-v", "print", "--output", "file.txt"}, "true", "file.txt"},
+		{[]string{"test", "-vo", "json", "cluster", "list"}, "true", "json"},
+		{[]string{"test", "-otext", "cluster", "list"}, "false", "text"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := broccli.Parse(testCase.args)
+		if err != nil || broccli.Flag("verbose") != testCase.verbose || broccli.Flag("output") != testCase.output {
+			t.Errorf("Parse() with %v returned invalid values: %v", testCase.args, err)
+		}
+	}
+
+	_, err := broccli.Parse([]string{"test", "-o", "xml", "cluster", "list"})
+	if err == nil || err.Error() != "Flag output: param value invalid: must be one of json, text" {
+		t.Errorf("Parse() should have validated global flag: %v", err)
+	}
+
+	_, err = broccli.Parse([]string{"test", "--verbos", "cluster", "list"})
+	if !errors.Is(err, ErrFlagUnknown) || err.Error() != "Flag --verbos: unknown flag (did you mean --verbose?)" {
+		t.Errorf("Parse() should have returned error for unknown flag before command: %v", err)
+	}
+
+	candidates := broccli.completions([]string{"-o", "json", "cl"})
+	if len(candidates) != 1 || candidates[0] != "cluster" {
+		t.Errorf("Completion should skip global flags before command: %v", candidates)
+	}
+
+	broccli.RunArgs(context.Background(), []string{"test", "cluster", "list", "--help"})
+
+	help := stdout.String()
+	if !strings.Contains(help, "Global flags:") || !strings.Contains(help, "--verbose") ||
+		strings.Contains(help, "Optional flags:") {
+		t.Errorf("Help should list global flags in a separate section:\n%s", help)
 	}
 }
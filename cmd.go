@@ -3,9 +3,6 @@ package broccli
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
-	"path"
 	"reflect"
 	"sort"
 	"strings"
@@ -24,6 +21,53 @@ type Command struct {
 	env       map[string]*param
 	handler   func(context.Context, *Broccli) int
 	options   commandOptions
+	commands  map[string]*Command
+	parent    *Command
+	bindings  []binding
+	groups    []flagGroup
+	// global holds flags added with Broccli.GlobalFlag, and it is set on top-level commands only
+	global *Command
+}
+
+func newCommand(
+	name, usage string,
+	handler func(ctx context.Context, cli *Broccli) int,
+	opts ...CommandOption,
+) *Command {
+	cmd := &Command{
+		name:     name,
+		usage:    usage,
+		flags:    map[string]*param{},
+		args:     map[string]*param{},
+		env:      map[string]*param{},
+		handler:  handler,
+		options:  commandOptions{},
+		commands: map[string]*Command{},
+	}
+	for _, opt := range opts {
+		opt(&(cmd.options))
+	}
+
+	return cmd
+}
+
+// Command adds a subcommand to a command and returns a pointer to it.  It takes the same arguments as Broccli.Command.
+// Subcommand inherits flags of its parent commands.  Handler can be nil in which case command is used only to group
+// its subcommands.
+func (c *Command) Command(
+	name, usage string,
+	handler func(ctx context.Context, cli *Broccli) int,
+	opts ...CommandOption,
+) *Command {
+	if c.commands == nil {
+		c.commands = map[string]*Command{}
+	}
+
+	cmd := newCommand(name, usage, handler, opts...)
+	cmd.parent = c
+	c.commands[name] = cmd
+
+	return cmd
 }
 
 // Flag adds a flag to a command and returns a pointer to Param instance.
@@ -51,6 +95,14 @@ func (c *Command) Flag(
 	for _, o := range opts {
 		o(&(c.flags[name].options))
 	}
+
+	if c.flags[name].options.variadic {
+		panic(fmt.Sprintf("flag %s cannot be variadic", name))
+	}
+
+	c.addFileFlag(c.flags[name])
+
+	c.flags[name].mustBeValid()
 }
 
 // Arg adds an argument to a command and returns a pointer to Param instance.  It is the same as adding flag except
@@ -60,8 +112,8 @@ func (c *Command) Arg(
 	types, flags int64,
 	opts ...ParamOption,
 ) {
-	if c.argsIdx > maxArgs-1 {
-		log.Fatal("Only 10 arguments are allowed")
+	if variadicArg := c.variadicArg(); variadicArg != nil {
+		panic(fmt.Sprintf("arg %s cannot be added after variadic arg %s", name, variadicArg.name))
 	}
 
 	if c.args == nil {
@@ -76,22 +128,19 @@ func (c *Command) Arg(
 		flags:            flags,
 		options:          paramOptions{},
 	}
-	if c.argsOrder == nil {
-		c.argsOrder = make([]string, maxArgs)
-	}
-
-	c.argsOrder[c.argsIdx] = name
-
+	c.argsOrder = append(c.argsOrder, name)
 	c.argsIdx++
 
 	for _, opt := range opts {
 		opt(&(c.args[name].options))
 	}
+
+	c.args[name].mustBeValid()
 }
 
 // Env adds a required environment variable to a command and returns a pointer to Param.  It's arguments are very
 // similar to ones in previous AddArg and AddFlag methods.
-func (c *Command) Env(name, usage string, types, flags int64, _ ...ParamOption) {
+func (c *Command) Env(name, usage string, types, flags int64, opts ...ParamOption) {
 	if c.env == nil {
 		c.env = map[string]*param{}
 	}
@@ -103,6 +152,11 @@ func (c *Command) Env(name, usage string, types, flags int64, _ ...ParamOption)
 		flags:     flags,
 		options:   paramOptions{},
 	}
+	for _, opt := range opts {
+		opt(&(c.env[name].options))
+	}
+
+	c.env[name].mustBeValid()
 }
 
 func (c *Command) sortedArgs() []string {
@@ -115,7 +169,7 @@ func (c *Command) sortedArgs() []string {
 
 		arg := c.args[argOrderedName]
 
-		if arg.flags&IsRequired > 0 {
+		if arg.flags&IsRequired > 0 && !arg.options.variadic {
 			argNamesSorted[idx] = argOrderedName
 			idx++
 		}
@@ -127,15 +181,34 @@ func (c *Command) sortedArgs() []string {
 
 		arg := c.args[argOrderedName]
 
-		if arg.flags&IsRequired == 0 {
+		if arg.flags&IsRequired == 0 && !arg.options.variadic {
 			argNamesSorted[idx] = argOrderedName
 			idx++
 		}
 	}
 
+	// variadic arg always takes the remaining values
+	if variadicArg := c.variadicArg(); variadicArg != nil {
+		argNamesSorted[idx] = variadicArg.name
+	}
+
 	return argNamesSorted
 }
 
+// variadicArg returns arg that captures all the remaining args, or nil when command does not have one.
+func (c *Command) variadicArg() *param {
+	if c.argsIdx == 0 {
+		return nil
+	}
+
+	arg := c.args[c.argsOrder[c.argsIdx-1]]
+	if !arg.options.variadic {
+		return nil
+	}
+
+	return arg
+}
+
 func (c *Command) sortedFlags() []string {
 	flagNames := reflect.ValueOf(c.flags).MapKeys()
 
@@ -150,6 +223,60 @@ func (c *Command) sortedFlags() []string {
 	return flagNamesSorted
 }
 
+// allFlags returns flags of the command together with the ones inherited from its parents and the global ones.  When
+// a flag with the same name is defined on more than one level, the one closest to the command wins.
+func (c *Command) allFlags() map[string]*param {
+	flags := map[string]*param{}
+
+	if c.parent != nil {
+		for name, flag := range c.parent.allFlags() {
+			flags[name] = flag
+		}
+	} else if c.global != nil {
+		for name, flag := range c.global.flags {
+			flags[name] = flag
+		}
+	}
+
+	for name, flag := range c.flags {
+		flags[name] = flag
+	}
+
+	return flags
+}
+
+// isGlobalFlag returns true when flag was added with Broccli.GlobalFlag.
+func (c *Command) isGlobalFlag(name string, flag *param) bool {
+	if c.parent != nil {
+		return c.parent.isGlobalFlag(name, flag)
+	}
+
+	return c.global != nil && c.global.flags[name] == flag
+}
+
+// allFlagGroups returns flag groups of the command and its parents.
+func (c *Command) allFlagGroups() []flagGroup {
+	if c.parent == nil {
+		return c.groups
+	}
+
+	return append(append([]flagGroup{}, c.parent.allFlagGroups()...), c.groups...)
+}
+
+func (c *Command) sortedAllFlags() []string {
+	flagNames := reflect.ValueOf(c.allFlags()).MapKeys()
+
+	flagNamesSorted := make([]string, len(flagNames))
+
+	for i, flagName := range flagNames {
+		flagNamesSorted[i] = flagName.String()
+	}
+
+	sort.Strings(flagNamesSorted)
+
+	return flagNamesSorted
+}
+
 func (c *Command) sortedEnv() []string {
 	envNames := reflect.ValueOf(c.env).MapKeys()
 
@@ -164,13 +291,131 @@ func (c *Command) sortedEnv() []string {
 	return envNamesSorted
 }
 
-// PrintHelp prints command usage information to stdout file.
-func (c *Command) printHelp() {
+func (c *Command) sortedCommands() []string {
+	return sortedVisibleCommands(c.commands)
+}
+
+// sortedVisibleCommands returns sorted names of commands that are not hidden.
+func sortedVisibleCommands(commands map[string]*Command) []string {
+	commandNamesSorted := []string{}
+
+	for name, cmd := range commands {
+		if !cmd.options.hidden {
+			commandNamesSorted = append(commandNamesSorted, name)
+		}
+	}
+
+	sort.Strings(commandNamesSorted)
+
+	return commandNamesSorted
+}
+
+// findCommand returns command with the name or alias, or nil when there is no such command.
+func findCommand(commands map[string]*Command, name string) *Command {
+	if cmd, ok := commands[name]; ok {
+		return cmd
+	}
+
+	for _, cmdName := range sortedCommandNames(commands) {
+		for _, alias := range commands[cmdName].options.aliases {
+			if alias == name {
+				return commands[cmdName]
+			}
+		}
+	}
+
+	return nil
+}
+
+func sortedCommandNames(commands map[string]*Command) []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// helpLine returns command name with its aliases and usage that are shown on the list of commands.
+func (c *Command) helpLine() string {
+	name := strings.Join(append([]string{c.name}, c.options.aliases...), ", ")
+
+	usage := c.usage
+	if c.options.deprecated {
+		usage += deprecationNote(c.options.deprecation)
+	}
+
+	return fmt.Sprintf("  %s\t%s\n", name, usage)
+}
+
+// deprecationNote returns note about deprecated command or flag that is appended to its usage.
+func deprecationNote(message string) string {
+	if message == "" {
+		return " (deprecated)"
+	}
+
+	return " (deprecated: " + message + ")"
+}
+
+// fullName returns names of the command and all its parents, separated with space.
+func (c *Command) fullName() string {
+	if c.parent == nil {
+		return c.name
+	}
+
+	return c.parent.fullName() + " " + c.name
+}
+
+// synopsis returns usage lines of the command.
+func (c *Command) synopsis(programName string) []string {
+	synopsis := []string{}
+
+	if c.handler != nil {
+		synopsis = append(synopsis, fmt.Sprintf("%s %s [FLAGS]%s", programName, c.fullName(), c.argsHelpLine()))
+	}
+
+	if len(c.commands) > 0 {
+		synopsis = append(synopsis, fmt.Sprintf("%s %s COMMAND", programName, c.fullName()))
+	}
+
+	return synopsis
+}
+
+// helpMessage returns command usage information.
+//
+//nolint:funlen
+func (c *Command) helpMessage(programName string) string {
 	var helpMessage strings.Builder
 
-	_, _ = fmt.Fprintf(&helpMessage, "\nUsage:  %s %s [FLAGS]%s\n\n", path.Base(os.Args[0]), c.name,
-		c.argsHelpLine())
-	_, _ = fmt.Fprintf(&helpMessage, "%s\n", c.usage)
+	_, _ = fmt.Fprintf(&helpMessage, "\n")
+
+	for _, synopsis := range c.synopsis(programName) {
+		_, _ = fmt.Fprintf(&helpMessage, "Usage:  %s\n", synopsis)
+	}
+
+	_, _ = fmt.Fprintf(&helpMessage, "\n%s\n", c.usage)
+
+	if len(c.commands) > 0 {
+		_, _ = fmt.Fprintf(&helpMessage, "\nCommands:\n")
+
+		tabFormatter := new(tabwriter.Writer)
+		tabFormatter.Init(
+			&helpMessage,
+			tabWriterMinWidthForCommand,
+			tabWriterTabWidth,
+			tabWriterPadding,
+			tabWriterPadChar,
+			0,
+		)
+
+		for _, commandName := range c.sortedCommands() {
+			_, _ = fmt.Fprint(tabFormatter, c.commands[commandName].helpLine())
+		}
+
+		_ = tabFormatter.Flush()
+	}
 
 	if len(c.env) > 0 {
 		_, _ = fmt.Fprintf(&helpMessage, "\nRequired environment variables:\n")
@@ -186,7 +431,7 @@ func (c *Command) printHelp() {
 		)
 
 		for _, envName := range c.sortedEnv() {
-			_, _ = fmt.Fprintf(tabFormatter, "%s\t%s\n", envName, c.env[envName].usage)
+			_, _ = fmt.Fprintf(tabFormatter, "%s\t%s\n", envName, c.env[envName].description())
 		}
 
 		_ = tabFormatter.Flush()
@@ -205,8 +450,14 @@ func (c *Command) printHelp() {
 	var requiredFlags string
 	var optionalFlags string
 
-	for _, flagName := range c.sortedFlags() {
-		flag := c.flags[flagName]
+	flags := c.allFlags()
+	for _, flagName := range c.sortedAllFlags() {
+		flag := flags[flagName]
+		// global flags are shown in a separate section
+		if c.isGlobalFlag(flagName, flag) {
+			continue
+		}
+
 		if flag.flags&IsRequired > 0 {
 			requiredFlags += flag.helpLine()
 		} else {
@@ -226,10 +477,23 @@ func (c *Command) printHelp() {
 		_ = tabFormatter.Flush()
 	}
 
-	_, err := fmt.Fprint(os.Stdout, helpMessage.String())
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Unable to build help message")
+	if groups := c.allFlagGroups(); len(groups) > 0 {
+		_, _ = fmt.Fprintf(&helpMessage, "\nFlag constraints:\n")
+		for _, group := range groups {
+			_, _ = fmt.Fprintf(&helpMessage, "  %s\n", group.helpLine())
+		}
+	}
+
+	if len(c.commands) > 0 {
+		_, _ = fmt.Fprintf(
+			&helpMessage,
+			"\nRun '%s %s COMMAND --help' for command syntax.\n",
+			programName,
+			c.fullName(),
+		)
 	}
+
+	return helpMessage.String()
 }
 
 func (c *Command) argsHelpLine() string {
@@ -241,13 +505,26 @@ func (c *Command) argsHelpLine() string {
 			flagOrderedName := c.argsOrder[argIdx]
 
 			arg := c.args[flagOrderedName]
+			if arg.options.variadic {
+				continue
+			}
+
 			if arg.flags&IsRequired > 0 {
-				argsRequired += " " + arg.valuePlaceholder
+				argsRequired += " " + arg.argPlaceholder()
 			} else {
-				argsOptional += " [" + arg.valuePlaceholder + "]"
+				argsOptional += " [" + arg.argPlaceholder() + "]"
 			}
 		}
 	}
 
-	return argsRequired + argsOptional
+	argsVariadic := ""
+	if variadicArg := c.variadicArg(); variadicArg != nil {
+		if variadicArg.minArgCount() > 0 {
+			argsVariadic = " " + variadicArg.argPlaceholder()
+		} else {
+			argsVariadic = " [" + variadicArg.argPlaceholder() + "]"
+		}
+	}
+
+	return argsRequired + argsOptional + argsVariadic
 }
@@ -46,7 +46,7 @@ func printHandler(_ context.Context, cli *broccli.Broccli) int {
 
 	file, err := os.Open(filepath.Clean(langFile))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error opening file %s: %s", langFile, err.Error())
+		fmt.Fprintf(cli.Stderr(), "error opening file %s: %s", langFile, err.Error())
 
 		return 1
 	}
@@ -77,7 +77,7 @@ func printHandler(_ context.Context, cli *broccli.Broccli) int {
 		lastName = " " + cli.Arg("last-name")
 	}
 
-	_, _ = fmt.Fprintf(os.Stdout, "%s, %s%s!", message, firstName, lastName)
+	_, _ = fmt.Fprintf(cli.Stdout(), "%s, %s%s!", message, firstName, lastName)
 
 	return 0
 }
@@ -2,6 +2,10 @@ package broccli
 
 type commandOptions struct {
 	onPostValidation func(c *Command) error
+	aliases          []string
+	hidden           bool
+	deprecated       bool
+	deprecation      string
 }
 
 // CommandOption defines an optional configuration function for commands, intended for specific use cases.
@@ -14,3 +18,27 @@ func OnPostValidation(fn func(c *Command) error) CommandOption {
 		opts.onPostValidation = fn
 	}
 }
+
+// WithAliases sets other names that command can be called with, eg. 'rm' for 'remove'.  Aliases are shown next to
+// the command name on the help screen.
+func WithAliases(aliases ...string) CommandOption {
+	return func(opts *commandOptions) {
+		opts.aliases = aliases
+	}
+}
+
+// Hidden omits command from the help screen, shell completion and documentation.  Command can still be run.
+func Hidden() CommandOption {
+	return func(opts *commandOptions) {
+		opts.hidden = true
+	}
+}
+
+// DeprecatedCommand marks command as deprecated.  Command still runs but a warning with the message, eg. "use
+// 'remove' instead", is printed before.  See Broccli.Warnings.
+func DeprecatedCommand(message string) CommandOption {
+	return func(opts *commandOptions) {
+		opts.deprecated = true
+		opts.deprecation = message
+	}
+}
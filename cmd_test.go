@@ -1,7 +1,10 @@
 package broccli
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -45,3 +48,85 @@ func TestCommandParams(t *testing.T) {
 		}
 	}
 }
+
+// TestCommandVariadicArg tests that variadic arg is always the last one.
+func TestCommandVariadicArg(t *testing.T) {
+	t.Parallel()
+
+	c := &Command{}
+	for i := 1; i <= 12; i++ {
+		c.Arg(fmt.Sprintf("arg%d", i), fmt.Sprintf("ARG%d", i), "Arg", TypeInt, 0)
+	}
+
+	c.Arg("files", "FILES", "Files", TypePathFile, IsRequired, Variadic(0, 3))
+
+	sa := c.sortedArgs()
+	if len(sa) != 13 || sa[12] != "files" {
+		t.Errorf("Variadic arg should be the last one instead of %v", sa)
+	}
+
+	if !strings.HasSuffix(c.argsHelpLine(), " [ARG12] FILES...") {
+		t.Errorf("Invalid args help line: %s", c.argsHelpLine())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Adding arg after variadic arg should have panicked")
+		}
+	}()
+
+	c.Arg("extra", "EXTRA", "Extra", TypeString, 0)
+}
+
+// TestCommandAliasesAndDeprecation tests command aliases, hidden commands and deprecation warnings.
+func TestCommandAliasesAndDeprecation(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	cli := newTestCLI(WithOutput(&stdout), WithErrorOutput(&stderr))
+	handler := func(_ context.Context, _ *Broccli) int { return 0 }
+
+	remove := cli.Command("remove", "Removes a file", handler, WithAliases("rm", "del"))
+	remove.Flag("force", "f", "", "Force removal", TypeBool, 0)
+	remove.Flag("recursive", "r", "", "Remove directories", TypeBool, 0, DeprecatedFlag("use --force instead"))
+	cli.Command("erase", "Erases a file", handler, DeprecatedCommand("use 'remove' instead"))
+	cli.Command("debug", "Prints debug info", handler, Hidden())
+
+	cmd, err := cli.Parse([]string{"test", "rm", "-f"})
+	if err != nil || cmd != remove || len(cli.Warnings()) != 0 {
+		t.Errorf("Parse() should have found command by alias: %v", err)
+	}
+
+	_, err = cli.Parse([]string{"test", "debug"})
+	if err != nil {
+		t.Errorf("Hidden command should still run: %v", err)
+	}
+
+	exitCode := cli.RunArgs(context.Background(), []string{"test", "del", "-r"})
+	if exitCode != 0 || stderr.String() != "WARNING: flag --recursive is deprecated: use --force instead\n" {
+		t.Errorf("RunArgs() should have printed flag deprecation warning:\n%s", stderr.String())
+	}
+
+	_, err = cli.Parse([]string{"test", "erase"})
+	if err != nil || len(cli.Warnings()) != 1 ||
+		cli.Warnings()[0] != "command erase is deprecated: use 'remove' instead" {
+		t.Errorf("Parse() should have returned command deprecation warning: %v", cli.Warnings())
+	}
+
+	cli.printHelp()
+
+	help := stdout.String()
+	if strings.Contains(help, "debug") || !strings.Contains(help, "remove, rm, del") ||
+		!strings.Contains(help, "Erases a file (deprecated: use 'remove' instead)") {
+		t.Errorf("Help should show aliases and deprecation and hide hidden commands:\n%s", help)
+	}
+
+	if candidates := cli.completions([]string{""}); strings.Join(candidates, ",") != "erase,remove" {
+		t.Errorf("Completion should skip hidden commands: %v", candidates)
+	}
+
+	if !strings.Contains(remove.helpMessage("test"), "(deprecated: use --force instead)") {
+		t.Errorf("Command help should show deprecated flag")
+	}
+}
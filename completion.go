@@ -0,0 +1,338 @@
+package broccli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// completeCommandName is a hidden command that is called by completion scripts to get the candidates.
+	completeCommandName = "__complete"
+	// completionCommandName is a name of the built-in command that prints completion script.
+	completionCommandName = "completion"
+	// completeFile is printed instead of candidates when shell should complete file paths.
+	completeFile = ":file"
+	// completeDirectory is printed instead of candidates when shell should complete directory paths.
+	completeDirectory = ":dir"
+)
+
+// CompletionCommand adds a built-in 'completion' command that prints completion script for a shell passed as its
+// argument.  Supported shells are bash, zsh and fish.
+func (c *Broccli) CompletionCommand() *Command {
+	cmd := c.Command(
+		completionCommandName,
+		"Prints shell completion script",
+		func(_ context.Context, cli *Broccli) int {
+			err := cli.GenerateCompletion(cli.Arg("shell"), cli.Stdout())
+			if err != nil {
+				fmt.Fprintf(cli.Stderr(), "ERROR: %s\n", err.Error())
+
+				return 1
+			}
+
+			return 0
+		},
+	)
+	cmd.Arg("shell", "SHELL", "One of bash, zsh or fish", TypeAlphanumeric, IsRequired,
+		WithCompletion(func(_ string) []string {
+			return []string{"bash", "fish", "zsh"}
+		}),
+	)
+
+	return cmd
+}
+
+// GenerateCompletion writes completion script for the specified shell to w.  Supported shells are bash, zsh and fish.
+// Scripts call the program to get the candidates, so flag values can be completed dynamically with WithCompletion.
+func (c *Broccli) GenerateCompletion(shell string, w io.Writer) error {
+	var script string
+
+	switch shell {
+	case "bash":
+		script = bashCompletionScript
+	case "zsh":
+		script = zshCompletionScript
+	case "fish":
+		script = fishCompletionScript
+	default:
+		return fmt.Errorf("%w: %s", ErrShellUnsupported, shell)
+	}
+
+	functionName := "_" + regexp.MustCompile("[^a-zA-Z0-9_]").ReplaceAllString(c.programName, "_") + "_completion"
+
+	script = strings.ReplaceAll(script, "{{PROGRAM}}", c.programName)
+	script = strings.ReplaceAll(script, "{{FUNCTION}}", functionName)
+
+	_, err := fmt.Fprint(w, script)
+	if err != nil {
+		return fmt.Errorf("error writing completion script: %w", err)
+	}
+
+	return nil
+}
+
+// printCompletions prints candidates for the last word in words, one per line.
+func (c *Broccli) printCompletions(words []string) {
+	for _, candidate := range c.completions(words) {
+		_, _ = fmt.Fprintln(c.options.stdout, candidate)
+	}
+}
+
+// completions returns candidates for the last word in words.  Words are the arguments without the program name.
+func (c *Broccli) completions(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	// global flags passed before the command name are skipped
+	globalIdx, _ := c.skipGlobalFlags(append([]string{c.programName}, words...))
+	cmdIdx := min(globalIdx-1, len(words)-1)
+	words = words[cmdIdx:]
+
+	current := words[len(words)-1]
+
+	if len(words) == 1 {
+		return filterCandidates(c.sortedCommands(), current)
+	}
+
+	cmd := findCommand(c.commands, words[0])
+	if cmd == nil {
+		return []string{}
+	}
+
+	// walk down the tree of subcommands the same way as Parse does, the last word is the one being completed
+	wordIdx := 1
+	for wordIdx < len(words)-1 {
+		nameIdx, err := skipFlags(c.commandFlags(cmd), words[:len(words)-1], wordIdx)
+		if err != nil || nameIdx >= len(words)-1 {
+			break
+		}
+
+		subcommand := findCommand(cmd.commands, words[nameIdx])
+		if subcommand == nil {
+			break
+		}
+
+		cmd = subcommand
+		wordIdx = nameIdx + 1
+	}
+
+	return c.commandCompletions(cmd, c.commandFlags(cmd), words[wordIdx:])
+}
+
+// commandCompletions returns candidates for the last word in words, which are the arguments after command name.
+func (c *Broccli) commandCompletions(cmd *Command, flags map[string]*param, words []string) []string {
+	current := words[len(words)-1]
+
+	// value of the flag passed as --name=value
+	if strings.HasPrefix(current, "-") && strings.Contains(current, "=") {
+		flagName, flagValue, _ := strings.Cut(current, "=")
+
+		flag := findFlag(flags, strings.TrimLeft(flagName, "-"))
+		if flag == nil || flag.valueType == TypeBool {
+			return []string{}
+		}
+
+		candidates := []string{}
+
+		for _, candidate := range flag.completions(flagValue) {
+			// file paths cannot be completed by shell when they are glued to the flag name
+			if candidate != completeFile && candidate != completeDirectory {
+				candidates = append(candidates, flagName+"="+candidate)
+			}
+		}
+
+		return candidates
+	}
+
+	positional, previousFlag := completionPosition(flags, words[:len(words)-1])
+	if previousFlag != nil {
+		return previousFlag.completions(current)
+	}
+
+	if strings.HasPrefix(current, "-") && positional >= 0 {
+		candidates := []string{"--help"}
+		for name, flag := range flags {
+			candidates = append(candidates, "--"+name)
+			if flag.alias != "" {
+				candidates = append(candidates, "-"+flag.alias)
+			}
+		}
+
+		sort.Strings(candidates)
+
+		return filterCandidates(candidates, current)
+	}
+
+	candidates := []string{}
+	if positional == 0 && len(cmd.commands) > 0 {
+		candidates = append(candidates, filterCandidates(cmd.sortedCommands(), current)...)
+	}
+
+	argNames := cmd.sortedArgs()
+	if positional < 0 {
+		positional = -positional - 1
+	}
+
+	if positional < len(argNames) {
+		candidates = append(candidates, cmd.args[argNames[positional]].completions(current)...)
+	} else if variadicArg := cmd.variadicArg(); variadicArg != nil {
+		candidates = append(candidates, variadicArg.completions(current)...)
+	}
+
+	return candidates
+}
+
+// completionPosition returns number of positional args in words and a flag when the last word is a flag that expects
+// a value.  Number of positional args is negative when words contain '--', and it is -(n+1) in such case.
+func completionPosition(flags map[string]*param, words []string) (int, *param) {
+	positional := 0
+	onlyArgs := false
+
+	var previousFlag *param
+
+	for _, word := range words {
+		if previousFlag != nil {
+			previousFlag = nil
+
+			continue
+		}
+
+		if onlyArgs || !strings.HasPrefix(word, "-") || word == "-" {
+			positional++
+
+			continue
+		}
+
+		if word == "--" {
+			onlyArgs = true
+
+			continue
+		}
+
+		flag := findFlag(flags, strings.TrimLeft(word, "-"))
+		if flag != nil && flag.valueType != TypeBool && !strings.Contains(word, "=") {
+			previousFlag = flag
+		}
+	}
+
+	if onlyArgs {
+		return -positional - 1, nil
+	}
+
+	return positional, previousFlag
+}
+
+// findFlag returns flag with the name or alias.
+func findFlag(flags map[string]*param, name string) *param {
+	if flag, ok := flags[name]; ok {
+		return flag
+	}
+
+	for _, flag := range flags {
+		if flag.alias != "" && flag.alias == name {
+			return flag
+		}
+	}
+
+	return nil
+}
+
+// completions returns candidates for value of the param.
+func (p *param) completions(current string) []string {
+	if p.options.completion != nil {
+		return filterCandidates(p.options.completion(current), current)
+	}
+
+	if len(p.options.choices) > 0 {
+		return filterCandidates(p.options.choices, current)
+	}
+
+	if p.options.valueType != nil {
+		return filterCandidates(p.options.valueType.Complete(current), current)
+	}
+
+	if p.valueType == TypePathFile {
+		if p.flags&IsDirectory > 0 {
+			return []string{completeDirectory}
+		}
+
+		return []string{completeFile}
+	}
+
+	return []string{}
+}
+
+func filterCandidates(candidates []string, prefix string) []string {
+	filtered := []string{}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			filtered = append(filtered, candidate)
+		}
+	}
+
+	return filtered
+}
+
+const bashCompletionScript = `# bash completion for {{PROGRAM}}
+{{FUNCTION}}() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    local candidates=($({{PROGRAM}} ` + completeCommandName + ` "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+
+    if [[ "${candidates[0]}" == "` + completeFile + `" ]]; then
+        compopt -o filenames 2>/dev/null
+        COMPREPLY=($(compgen -f -- "${cur}"))
+    elif [[ "${candidates[0]}" == "` + completeDirectory + `" ]]; then
+        compopt -o filenames 2>/dev/null
+        COMPREPLY=($(compgen -d -- "${cur}"))
+    else
+        COMPREPLY=("${candidates[@]}")
+    fi
+}
+
+complete -F {{FUNCTION}} {{PROGRAM}}
+`
+
+const zshCompletionScript = `#compdef {{PROGRAM}}
+# zsh completion for {{PROGRAM}}
+{{FUNCTION}}() {
+    local -a candidates
+    candidates=("${(@f)$({{PROGRAM}} ` + completeCommandName + ` "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    candidates=(${candidates:#})
+
+    if [[ "${candidates[1]}" == "` + completeFile + `" ]]; then
+        _files
+    elif [[ "${candidates[1]}" == "` + completeDirectory + `" ]]; then
+        _files -/
+    else
+        compadd -- "${candidates[@]}"
+    fi
+}
+
+compdef {{FUNCTION}} {{PROGRAM}}
+`
+
+const fishCompletionScript = `# fish completion for {{PROGRAM}}
+function {{FUNCTION}}
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    set -l candidates ({{PROGRAM}} ` + completeCommandName + ` $tokens[2..-1] "$current" 2>/dev/null)
+
+    switch "$candidates[1]"
+        case "` + completeFile + `"
+            __fish_complete_path "$current"
+        case "` + completeDirectory + `"
+            __fish_complete_directories "$current"
+        case '*'
+            printf '%s\n' $candidates
+    end
+end
+
+complete -c {{PROGRAM}} -f -a '({{FUNCTION}})'
+`
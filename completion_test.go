@@ -0,0 +1,82 @@
+package broccli
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCompletions tests candidates returned for shell completion.
+func TestCompletions(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	c.CompletionCommand()
+
+	deploy := c.Command("deploy", "Deploys", func(_ context.Context, _ *Broccli) int { return 0 })
+	deploy.Flag("file", "f", "PATH", "File", TypePathFile, 0)
+	deploy.Flag("dir", "d", "DIR", "Directory", TypePathFile, IsDirectory)
+	deploy.Flag("force", "", "", "Force", TypeBool, 0)
+	deploy.Flag("output", "o", "FORMAT", "Output format", TypeString, 0, OneOf("json", "yaml", "table"))
+	deploy.Flag("region", "r", "REGION", "Region", TypeString, 0, WithCompletion(func(_ string) []string {
+		return []string{"eu-west-1", "eu-central-1", "us-east-1"}
+	}))
+	deploy.Arg("target", "TARGET", "Target", TypeString, IsRequired, WithCompletion(func(_ string) []string {
+		return []string{"prod", "staging"}
+	}))
+
+	cluster := c.Command("cluster", "Clusters", nil)
+	cluster.Flag("cluster-name", "c", "NAME", "Name of the cluster", TypeString, 0)
+	cluster.Command("list", "Lists", func(_ context.Context, _ *Broccli) int { return 0 })
+	node := cluster.Command("node", "Nodes", func(_ context.Context, _ *Broccli) int { return 0 })
+	node.Flag("zone", "z", "ZONE", "Zone", TypeString, 0)
+
+	testCases := []struct {
+		words    []string
+		expected []string
+	}{
+		{[]string{""}, []string{"cluster", "completion", "deploy"}},
+		{[]string{"de"}, []string{"deploy"}},
+		{[]string{"cluster", ""}, []string{"list", "node"}},
+		{[]string{"cluster", "-c", "main", "node", "--z"}, []string{"--zone"}},
+		{[]string{"cluster", "-cmain", "n"}, []string{"node"}},
+		{[]string{"completion", ""}, []string{"bash", "fish", "zsh"}},
+		{[]string{"deploy", "--f"}, []string{"--file", "--force"}},
+		{[]string{"deploy", "-f", ""}, []string{completeFile}},
+		{[]string{"deploy", "--dir", ""}, []string{completeDirectory}},
+		{[]string{"deploy", "--region", "eu"}, []string{"eu-west-1", "eu-central-1"}},
+		{[]string{"deploy", "--region=us"}, []string{"--region=us-east-1"}},
+		{[]string{"deploy", "-o", ""}, []string{"json", "yaml", "table"}},
+		{[]string{"deploy", "--force", "p"}, []string{"prod"}},
+		{[]string{"deploy", "prod", ""}, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		got := c.completions(testCase.words)
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("Completions for %v should be %v instead of %v", testCase.words, testCase.expected, got)
+		}
+	}
+}
+
+// TestGenerateCompletion tests generating completion scripts.
+func TestGenerateCompletion(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var script bytes.Buffer
+
+		err := c.GenerateCompletion(shell, &script)
+		if err != nil || !strings.Contains(script.String(), "Example __complete") {
+			t.Errorf("Completion script for %s should be generated", shell)
+		}
+	}
+
+	if c.GenerateCompletion("tcsh", &bytes.Buffer{}) == nil {
+		t.Errorf("Completion script for unsupported shell should fail")
+	}
+}
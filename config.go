@@ -0,0 +1,226 @@
+package broccli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func errConfigFileInvalidInPath(path string, reason string) error {
+	return fmt.Errorf("%w: %s: %s", ErrConfigFileInvalid, path, reason)
+}
+
+// configValue is a value of a flag loaded from the config file.  Key is the full path to the value in the file and it
+// is used in error messages.
+type configValue struct {
+	key    string
+	values []string
+}
+
+// ConfigFlag adds a flag that is available in every command and takes a path to the config file with flag values.
+// Keys in the file are flag names.  Values for a specific command can be put in a section named after the command,
+// eg. [cluster node] in key=value file or nested objects in JSON file.  Values from more specific sections override
+// the general ones.  Files with .json extension or starting with '{' are parsed as JSON, other files as key=value
+// lines.  Options such as WithDefault or FromEnv can be used to set a path to site-wide config file.  When the file
+// comes from the default value and it does not exist, it is skipped.
+// Flag value taken from the command line or environment variables takes precedence over the config file, and the
+// config file takes precedence over the default value.
+func (c *Broccli) ConfigFlag(name, alias, usage string, opts ...ParamOption) {
+	c.configFlag = &param{
+		name:             name,
+		alias:            alias,
+		usage:            usage,
+		valuePlaceholder: "PATH",
+		valueType:        TypePathFile,
+		flags:            IsRegularFile,
+		options:          paramOptions{},
+	}
+	for _, opt := range opts {
+		opt(&(c.configFlag.options))
+	}
+}
+
+// loadConfig reads path to the config file from flags and loads values for the command from that file.
+func (c *Broccli) loadConfig(cmd *Command, line *commandLine) error {
+	c.configValues = map[string]configValue{}
+	c.configPath = ""
+
+	if c.configFlag == nil {
+		return nil
+	}
+
+	// command has its own flag with the same name
+	if _, exists := cmd.allFlags()[c.configFlag.name]; exists {
+		return nil
+	}
+
+	configPath := line.value(c.configFlag)
+	configPath, source := c.flagValueWithFallback(c.configFlag, configPath)
+	if configPath == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) && source == sourceDefaultValue {
+		return nil
+	}
+
+	err := c.configFlag.validateValue(configPath)
+	if err == nil {
+		c.configPath = configPath
+		c.configValues, err = readConfigFile(configPath, cmd)
+	}
+
+	if err != nil {
+		return &ParamError{Kind: ParamFlag, Name: c.configFlag.name, Value: configPath, Source: source, Err: err}
+	}
+
+	return nil
+}
+
+// readConfigFile returns flag values for the command from config file.
+func readConfigFile(configPath string, cmd *Command) (map[string]configValue, error) {
+	dat, err := os.ReadFile(filepath.Clean(configPath))
+	if err != nil {
+		return nil, errFileOpenInPath("read config", configPath)
+	}
+
+	commandPath := strings.Fields(cmd.fullName())
+
+	if strings.EqualFold(filepath.Ext(configPath), ".json") || bytes.HasPrefix(bytes.TrimSpace(dat), []byte("{")) {
+		return parseJSONConfig(configPath, dat, commandPath)
+	}
+
+	return parseKeyValueConfig(configPath, dat, commandPath)
+}
+
+// parseJSONConfig gets values from JSON object.  Nested objects named after commands contain values for these
+// commands.
+func parseJSONConfig(configPath string, dat []byte, commandPath []string) (map[string]configValue, error) {
+	decoder := json.NewDecoder(bytes.NewReader(dat))
+	decoder.UseNumber()
+
+	var section map[string]interface{}
+
+	err := decoder.Decode(&section)
+	if err != nil {
+		return nil, errConfigFileInvalidInPath(configPath, err.Error())
+	}
+
+	values := map[string]configValue{}
+	keyPrefix := ""
+
+	for level := 0; section != nil; level++ {
+		for key, value := range section {
+			configValues, ok := jsonConfigValues(value)
+			if !ok {
+				continue
+			}
+
+			values[key] = configValue{key: keyPrefix + key, values: configValues}
+		}
+
+		if level >= len(commandPath) {
+			break
+		}
+
+		section, _ = section[commandPath[level]].(map[string]interface{})
+		keyPrefix += commandPath[level] + "."
+	}
+
+	return values, nil
+}
+
+// jsonConfigValues converts JSON value to a list of strings.  Second returned value is false when value is not
+// a flag value, eg. it is an object.
+func jsonConfigValues(value interface{}) ([]string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return []string{typedValue}, true
+	case json.Number:
+		return []string{typedValue.String()}, true
+	case bool:
+		return []string{fmt.Sprintf("%t", typedValue)}, true
+	case []interface{}:
+		values := make([]string, 0, len(typedValue))
+
+		for _, item := range typedValue {
+			itemValues, ok := jsonConfigValues(item)
+			if !ok {
+				return nil, false
+			}
+
+			values = append(values, itemValues...)
+		}
+
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// parseKeyValueConfig gets values from lines in 'key = value' format.  Lines starting with '#' or ';' are comments.
+// Lines such as '[cluster node]' start a section with values for a command.  Values can be put in double quotes.
+func parseKeyValueConfig(configPath string, dat []byte, commandPath []string) (map[string]configValue, error) {
+	values := map[string]configValue{}
+	// level of values that were set, so that values from more specific sections are not overwritten
+	levels := map[string]int{}
+
+	sectionLevel := 0
+	sectionPrefix := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(dat))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section := strings.Fields(strings.ReplaceAll(line[1:len(line)-1], ".", " "))
+			sectionLevel = configSectionLevel(section, commandPath)
+			sectionPrefix = strings.Join(section, ".") + "."
+
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, errConfigFileInvalidInPath(
+				configPath,
+				fmt.Sprintf("line %d is not in key=value format", lineNumber),
+			)
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), "\"")
+
+		if sectionLevel < 0 || (levels[key] > sectionLevel) {
+			continue
+		}
+
+		values[key] = configValue{key: sectionPrefix + key, values: []string{value}}
+		levels[key] = sectionLevel
+	}
+
+	return values, nil
+}
+
+// configSectionLevel returns number of command names in section when section applies to the command, and -1
+// when it does not.
+func configSectionLevel(section []string, commandPath []string) int {
+	if len(section) > len(commandPath) {
+		return -1
+	}
+
+	for i, name := range section {
+		if commandPath[i] != name {
+			return -1
+		}
+	}
+
+	return len(section)
+}
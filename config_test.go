@@ -0,0 +1,114 @@
+package broccli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestConfigParsing tests parsing values for a command from JSON and key=value config files.
+func TestConfigParsing(t *testing.T) {
+	t.Parallel()
+
+	commandPath := []string{"cluster", "node"}
+
+	jsonValues, err := parseJSONConfig("c.json", []byte(`{
+		"region": "eu", "count": 3, "tags": ["a", "b"], "verbose": true,
+		"cluster": {"region": "us", "node": {"count": 5}},
+		"other": {"region": "ap"}
+	}`), commandPath)
+	if err != nil {
+		t.Errorf("JSON config should parse")
+	}
+
+	if jsonValues["region"].values[0] != "us" || jsonValues["region"].key != "cluster.region" {
+		t.Errorf("JSON config value from command section should override the general one")
+	}
+
+	if jsonValues["count"].values[0] != "5" || len(jsonValues["tags"].values) != 2 ||
+		jsonValues["verbose"].values[0] != "true" {
+		t.Errorf("JSON config values were not parsed properly")
+	}
+
+	kvValues, err := parseKeyValueConfig("c.conf", []byte(`
+# comment
+region = eu
+count = 3
+
+[cluster node]
+count = "5"
+
+[cluster]
+region = us
+count = 4
+
+[other]
+region = ap
+`), commandPath)
+	if err != nil {
+		t.Errorf("key=value config should parse")
+	}
+
+	if kvValues["region"].values[0] != "us" || kvValues["count"].values[0] != "5" ||
+		kvValues["count"].key != "cluster.node.count" {
+		t.Errorf("key=value config values from more specific sections should be used")
+	}
+
+	_, err = parseKeyValueConfig("c.conf", []byte("region"), commandPath)
+	if err == nil {
+		t.Errorf("key=value config with invalid line should fail")
+	}
+}
+
+// TestCLIConfigFlag tests flags getting values from the config file.
+func TestCLIConfigFlag(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "app.conf")
+
+	err := os.WriteFile(configPath, []byte("region = eu\nlevel = x\n[cmd1]\nlevel = 4\n"), 0o600)
+	if err != nil {
+		t.Fatal("error writing config file")
+	}
+
+	var stderr bytes.Buffer
+
+	c := newTestCLI(WithErrorOutput(&stderr))
+	c.ConfigFlag("config", "", "Path to config file", WithDefault(filepath.Join(t.TempDir(), "missing.conf")))
+	cmd1 := c.Command("cmd1", "Checks config", func(_ context.Context, c *Broccli) int {
+		if c.Flag("region") != "eu" || c.Flag("level") != "4" {
+			return 3
+		}
+
+		return 2
+	})
+	cmd1.Flag("region", "r", "REGION", "Region", TypeAlphanumeric, IsRequired)
+	cmd1.Flag("level", "l", "LEVEL", "Level", TypeInt, 0)
+	cmd2 := c.Command("cmd2", "Checks config", func(_ context.Context, _ *Broccli) int {
+		return 2
+	})
+	cmd2.Flag("level", "l", "LEVEL", "Level", TypeInt, 0)
+
+	got := c.RunArgs(context.Background(), []string{"test", "cmd1"})
+	if got != 1 {
+		t.Errorf("CLI.RunArgs() should have returned 1 instead of %d", got)
+	}
+
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "--config", configPath})
+	if got != 2 {
+		t.Errorf("CLI.RunArgs() should have returned 2 instead of %d", got)
+	}
+
+	got = c.RunArgs(context.Background(), []string{"test", "cmd1", "--config", configPath, "-r", "us"})
+	if got != 3 {
+		t.Errorf("CLI.RunArgs() should have returned 3 instead of %d", got)
+	}
+
+	got = c.RunArgs(context.Background(), []string{"test", "cmd2", "--config", configPath})
+	if got != 1 || !strings.Contains(stderr.String(), "(from config file "+configPath+" key level)") {
+		t.Errorf("CLI.RunArgs() should have failed with the config file and key in the error")
+	}
+}
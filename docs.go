@@ -0,0 +1,362 @@
+package broccli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const docsFilePerm = 0o644
+
+// GenerateManPages writes roff man pages to dir.  There is one page per command, eg. 'mytool-deploy.1', and an index
+// page named after the program, eg. 'mytool.1', listing all commands.
+func (c *Broccli) GenerateManPages(dir string) error {
+	pages := map[string]string{
+		c.programName + ".1": c.manPage(),
+	}
+
+	for _, cmd := range c.allCommands() {
+		pages[c.docsFileName(cmd)+".1"] = c.commandManPage(cmd)
+	}
+
+	return writeDocs(dir, pages)
+}
+
+// GenerateMarkdown writes Markdown reference docs to dir.  There is one file per command, eg. 'mytool-deploy.md', and
+// an index file named after the program, eg. 'mytool.md', linking to all commands.
+func (c *Broccli) GenerateMarkdown(dir string) error {
+	pages := map[string]string{
+		c.programName + ".md": c.markdownPage(),
+	}
+
+	for _, cmd := range c.allCommands() {
+		pages[c.docsFileName(cmd)+".md"] = c.commandMarkdownPage(cmd)
+	}
+
+	return writeDocs(dir, pages)
+}
+
+func writeDocs(dir string, pages map[string]string) error {
+	for fileName, contents := range pages {
+		err := os.WriteFile(filepath.Join(dir, fileName), []byte(contents), docsFilePerm)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", fileName, err)
+		}
+	}
+
+	return nil
+}
+
+// allCommands returns all commands, including subcommands, sorted by their full name.
+func (c *Broccli) allCommands() []*Command {
+	commands := []*Command{}
+
+	var walk func(cmd *Command)
+	walk = func(cmd *Command) {
+		commands = append(commands, cmd)
+		for _, name := range cmd.sortedCommands() {
+			walk(cmd.commands[name])
+		}
+	}
+
+	for _, name := range c.sortedCommands() {
+		walk(c.commands[name])
+	}
+
+	return commands
+}
+
+// docsFileName returns name of the docs file for command without an extension, eg. 'mytool-cluster-node'.
+func (c *Broccli) docsFileName(cmd *Command) string {
+	return c.programName + "-" + strings.ReplaceAll(cmd.fullName(), " ", "-")
+}
+
+func (c *Broccli) manPage() string {
+	var page strings.Builder
+
+	_, _ = fmt.Fprintf(&page, ".TH \"%s\" \"1\" \"\" \"%s\" \"%s Manual\"\n",
+		roffEscape(strings.ToUpper(c.programName)), roffEscape(c.programName), roffEscape(c.name))
+	_, _ = fmt.Fprintf(&page, ".SH NAME\n%s \\- %s\n", roffEscape(c.programName), roffEscape(c.usage))
+	_, _ = fmt.Fprintf(&page, ".SH SYNOPSIS\n\\fB%s\\fR COMMAND [FLAGS]\n", roffEscape(c.programName))
+	_, _ = fmt.Fprintf(&page, ".SH DESCRIPTION\n%s\n", roffEscape(c.usage))
+
+	_, _ = fmt.Fprintf(&page, ".SH COMMANDS\n")
+	for _, name := range c.sortedCommands() {
+		_, _ = fmt.Fprintf(&page, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(name), roffEscape(c.commands[name].usage))
+	}
+
+	if globalFlags := c.globalFlags(); len(globalFlags) > 0 {
+		_, _ = fmt.Fprintf(&page, ".SH GLOBAL FLAGS\n")
+		for _, flag := range globalFlags {
+			_, _ = fmt.Fprint(&page, manFlag(flag))
+		}
+	}
+
+	if len(c.env) > 0 {
+		_, _ = fmt.Fprintf(&page, ".SH ENVIRONMENT\n")
+		for _, name := range c.sortedEnv() {
+			_, _ = fmt.Fprintf(&page, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(name), roffEscape(c.env[name].description()))
+		}
+	}
+
+	if c.author != "" {
+		_, _ = fmt.Fprintf(&page, ".SH AUTHOR\n%s\n", roffEscape(c.author))
+	}
+
+	seeAlso := []string{}
+	for _, cmd := range c.allCommands() {
+		seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s\\fR(1)", roffEscape(c.docsFileName(cmd))))
+	}
+
+	_, _ = fmt.Fprintf(&page, ".SH SEE ALSO\n%s\n", strings.Join(seeAlso, ", "))
+
+	return page.String()
+}
+
+func (c *Broccli) commandManPage(cmd *Command) string {
+	var page strings.Builder
+
+	fileName := c.docsFileName(cmd)
+
+	_, _ = fmt.Fprintf(&page, ".TH \"%s\" \"1\" \"\" \"%s\" \"%s Manual\"\n",
+		roffEscape(strings.ToUpper(fileName)), roffEscape(c.programName), roffEscape(c.name))
+	_, _ = fmt.Fprintf(&page, ".SH NAME\n%s \\- %s\n", roffEscape(fileName), roffEscape(cmd.usage))
+	_, _ = fmt.Fprintf(&page, ".SH SYNOPSIS\n")
+
+	for _, synopsis := range cmd.synopsis(c.programName) {
+		_, _ = fmt.Fprintf(&page, "%s\n.br\n", roffEscape(synopsis))
+	}
+
+	_, _ = fmt.Fprintf(&page, ".SH DESCRIPTION\n%s\n", roffEscape(cmd.usage))
+
+	if len(cmd.commands) > 0 {
+		_, _ = fmt.Fprintf(&page, ".SH COMMANDS\n")
+		for _, name := range cmd.sortedCommands() {
+			_, _ = fmt.Fprintf(&page, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(name), roffEscape(cmd.commands[name].usage))
+		}
+	}
+
+	if cmd.argsIdx > 0 {
+		_, _ = fmt.Fprintf(&page, ".SH ARGUMENTS\n")
+		for _, name := range cmd.sortedArgs() {
+			arg := cmd.args[name]
+			_, _ = fmt.Fprintf(&page, ".TP\n\\fI%s\\fR\n%s\n", roffEscape(arg.argPlaceholder()),
+				roffEscape(arg.description()))
+		}
+	}
+
+	if flags := cmd.commandFlags(); len(flags) > 0 {
+		_, _ = fmt.Fprintf(&page, ".SH FLAGS\n")
+		for _, flag := range flags {
+			_, _ = fmt.Fprint(&page, manFlag(flag))
+		}
+	}
+
+	if globalFlags := c.globalFlags(); len(globalFlags) > 0 {
+		_, _ = fmt.Fprintf(&page, ".SH GLOBAL FLAGS\n")
+		for _, flag := range globalFlags {
+			_, _ = fmt.Fprint(&page, manFlag(flag))
+		}
+	}
+
+	if len(cmd.env) > 0 {
+		_, _ = fmt.Fprintf(&page, ".SH ENVIRONMENT\n")
+		for _, name := range cmd.sortedEnv() {
+			_, _ = fmt.Fprintf(&page, ".TP\n\\fB%s\\fR\n%s\n", roffEscape(name), roffEscape(cmd.env[name].description()))
+		}
+	}
+
+	_, _ = fmt.Fprintf(&page, ".SH SEE ALSO\n\\fB%s\\fR(1)\n", roffEscape(c.programName))
+
+	return page.String()
+}
+
+// globalFlags returns flags that are available in every command, ie. the config file flag and the global flags.
+func (c *Broccli) globalFlags() []*param {
+	flags := []*param{}
+	if c.configFlag != nil {
+		flags = append(flags, c.configFlag)
+	}
+
+	for _, name := range c.global.sortedFlags() {
+		flags = append(flags, c.global.flags[name])
+	}
+
+	return flags
+}
+
+// commandFlags returns sorted flags of the command and its parents without the global ones, which are documented
+// separately.
+func (c *Command) commandFlags() []*param {
+	flags := []*param{}
+
+	allFlags := c.allFlags()
+	for _, name := range c.sortedAllFlags() {
+		if !c.isGlobalFlag(name, allFlags[name]) {
+			flags = append(flags, allFlags[name])
+		}
+	}
+
+	return flags
+}
+
+func manFlag(flag *param) string {
+	names := "\\fB\\-\\-" + roffEscape(flag.name) + "\\fR"
+	if flag.alias != "" {
+		names = "\\fB\\-" + roffEscape(flag.alias) + "\\fR, " + names
+	}
+
+	if flag.valuePlaceholder != "" {
+		names += " \\fI" + roffEscape(flag.valuePlaceholder) + "\\fR"
+	}
+
+	description := flag.description()
+	if flag.flags&IsRequired > 0 {
+		description += " (required)"
+	}
+
+	return fmt.Sprintf(".TP\n%s\n%s\n", names, roffEscape(description))
+}
+
+// roffEscape escapes characters that have special meaning in roff.
+func roffEscape(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\e")
+	text = strings.ReplaceAll(text, "-", "\\-")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (c *Broccli) markdownPage() string {
+	var page strings.Builder
+
+	_, _ = fmt.Fprintf(&page, "# %s\n\n%s\n\n", c.programName, c.usage)
+
+	if c.author != "" {
+		_, _ = fmt.Fprintf(&page, "Author: %s\n\n", c.author)
+	}
+
+	_, _ = fmt.Fprintf(&page, "## Usage\n\n```\n%s COMMAND [FLAGS]\n```\n\n", c.programName)
+
+	_, _ = fmt.Fprintf(&page, "## Commands\n\n| Command | Description |\n| --- | --- |\n")
+	for _, cmd := range c.allCommands() {
+		_, _ = fmt.Fprintf(&page, "| [%s](%s.md) | %s |\n", cmd.fullName(), c.docsFileName(cmd),
+			markdownEscape(cmd.usage))
+	}
+
+	if globalFlags := c.globalFlags(); len(globalFlags) > 0 {
+		_, _ = fmt.Fprintf(&page, "\n## Global flags\n\n%s", markdownFlags(globalFlags))
+	}
+
+	if len(c.env) > 0 {
+		envVars := make([]*param, 0, len(c.env))
+		for _, name := range c.sortedEnv() {
+			envVars = append(envVars, c.env[name])
+		}
+
+		_, _ = fmt.Fprintf(&page, "\n## Environment variables\n\n%s", markdownEnv(envVars))
+	}
+
+	return page.String()
+}
+
+func (c *Broccli) commandMarkdownPage(cmd *Command) string {
+	var page strings.Builder
+
+	_, _ = fmt.Fprintf(&page, "# %s %s\n\n%s\n\n", c.programName, cmd.fullName(), cmd.usage)
+	_, _ = fmt.Fprintf(&page, "## Usage\n\n```\n%s\n```\n", strings.Join(cmd.synopsis(c.programName), "\n"))
+
+	if len(cmd.commands) > 0 {
+		_, _ = fmt.Fprintf(&page, "\n## Commands\n\n| Command | Description |\n| --- | --- |\n")
+		for _, name := range cmd.sortedCommands() {
+			subcommand := cmd.commands[name]
+			_, _ = fmt.Fprintf(&page, "| [%s](%s.md) | %s |\n", name, c.docsFileName(subcommand),
+				markdownEscape(subcommand.usage))
+		}
+	}
+
+	if cmd.argsIdx > 0 {
+		_, _ = fmt.Fprintf(&page, "\n## Arguments\n\n| Argument | Description |\n| --- | --- |\n")
+		for _, name := range cmd.sortedArgs() {
+			arg := cmd.args[name]
+			_, _ = fmt.Fprintf(&page, "| `%s` | %s |\n", arg.argPlaceholder(), markdownEscape(markdownRequired(arg)))
+		}
+	}
+
+	if flags := cmd.commandFlags(); len(flags) > 0 {
+		_, _ = fmt.Fprintf(&page, "\n## Flags\n\n%s", markdownFlags(flags))
+	}
+
+	if globalFlags := c.globalFlags(); len(globalFlags) > 0 {
+		_, _ = fmt.Fprintf(&page, "\n## Global flags\n\n%s", markdownFlags(globalFlags))
+	}
+
+	if len(cmd.env) > 0 {
+		envVars := make([]*param, 0, len(cmd.env))
+		for _, name := range cmd.sortedEnv() {
+			envVars = append(envVars, cmd.env[name])
+		}
+
+		_, _ = fmt.Fprintf(&page, "\n## Environment variables\n\n%s", markdownEnv(envVars))
+	}
+
+	_, _ = fmt.Fprintf(&page, "\n## See also\n\n- [%s](%s.md)\n", c.programName, c.programName)
+
+	return page.String()
+}
+
+func markdownFlags(flags []*param) string {
+	var table strings.Builder
+
+	_, _ = fmt.Fprintf(&table, "| Flag | Description |\n| --- | --- |\n")
+
+	for _, flag := range flags {
+		names := "`--" + flag.name
+		if flag.valuePlaceholder != "" {
+			names += " " + flag.valuePlaceholder
+		}
+
+		names += "`"
+		if flag.alias != "" {
+			names = "`-" + flag.alias + "`, " + names
+		}
+
+		_, _ = fmt.Fprintf(&table, "| %s | %s |\n", names, markdownEscape(markdownRequired(flag)))
+	}
+
+	return table.String()
+}
+
+func markdownEnv(envVars []*param) string {
+	var table strings.Builder
+
+	_, _ = fmt.Fprintf(&table, "| Variable | Description |\n| --- | --- |\n")
+
+	for _, envVar := range envVars {
+		_, _ = fmt.Fprintf(&table, "| `%s` | %s |\n", envVar.name, markdownEscape(envVar.description()))
+	}
+
+	return table.String()
+}
+
+func markdownRequired(p *param) string {
+	if p.flags&IsRequired > 0 {
+		return p.description() + " (required)"
+	}
+
+	return p.description()
+}
+
+// markdownEscape escapes characters that break Markdown tables.
+func markdownEscape(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,108 @@
+package broccli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateDocs tests generating man pages and Markdown docs for all commands.
+func TestGenerateDocs(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	c.Env("APP_HOME", "Home directory")
+
+	cluster := c.Command("cluster", "Manages clusters", nil)
+	cluster.Flag("cluster-name", "c", "NAME", "Name of the cluster", TypeString, IsRequired)
+	add := cluster.Command("add-node", "Adds a node | server", func(_ context.Context, _ *Broccli) int { return 0 })
+	add.Arg("node", "NODE", "Name of the node", TypeString, IsRequired)
+	add.Flag("force", "f", "", "Force adding", TypeBool, 0)
+
+	dir := t.TempDir()
+
+	err := c.GenerateManPages(dir)
+	if err != nil {
+		t.Fatalf("Man pages should be generated: %s", err.Error())
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "Example-cluster-add-node.1"))
+	if err != nil {
+		t.Fatal("Man page for a subcommand should be generated")
+	}
+
+	if !strings.Contains(string(page), "\\fB\\-c\\fR, \\fB\\-\\-cluster\\-name\\fR \\fINAME\\fR") ||
+		!strings.Contains(string(page), "Example cluster add\\-node [FLAGS] NODE") {
+		t.Errorf("Man page for a subcommand should contain inherited flags and synopsis")
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "Example.1"))
+	if err != nil || !strings.Contains(string(index), "\\fBExample\\-cluster\\fR(1)") ||
+		!strings.Contains(string(index), "APP_HOME") {
+		t.Errorf("Man page index should be generated")
+	}
+
+	err = c.GenerateMarkdown(dir)
+	if err != nil {
+		t.Fatalf("Markdown docs should be generated: %s", err.Error())
+	}
+
+	doc, err := os.ReadFile(filepath.Join(dir, "Example-cluster.md"))
+	if err != nil || !strings.Contains(string(doc), "| [add-node](Example-cluster-add-node.md) | Adds a node \\| server |") {
+		t.Errorf("Markdown doc for a command should link to subcommands")
+	}
+
+	index, err = os.ReadFile(filepath.Join(dir, "Example.md"))
+	if err != nil || !strings.Contains(string(index), "[cluster add-node](Example-cluster-add-node.md)") {
+		t.Errorf("Markdown index should link to all commands")
+	}
+}
+
+// TestGenerateDocsGlobalFlags tests that global flags are listed in the global sections only.
+func TestGenerateDocsGlobalFlags(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	c.GlobalFlag("verbose", "v", "", "Verbose output", TypeBool, 0)
+	c.Command("start", "Starts", func(_ context.Context, _ *Broccli) int { return 0 })
+
+	dir := t.TempDir()
+
+	err := c.GenerateManPages(dir)
+	if err != nil {
+		t.Fatalf("Man pages should be generated: %s", err.Error())
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "Example-start.1"))
+	if err != nil {
+		t.Fatal("Man page for a command should be generated")
+	}
+
+	if strings.Contains(string(page), ".SH FLAGS") ||
+		!strings.Contains(string(page), ".SH GLOBAL FLAGS\n.TP\n\\fB\\-v\\fR, \\fB\\-\\-verbose\\fR") {
+		t.Errorf("Man page should list global flags under GLOBAL FLAGS only:\n%s", page)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "Example.1"))
+	if err != nil || !strings.Contains(string(index), "\\fB\\-\\-verbose\\fR") {
+		t.Errorf("Man page index should list global flags")
+	}
+
+	err = c.GenerateMarkdown(dir)
+	if err != nil {
+		t.Fatalf("Markdown docs should be generated: %s", err.Error())
+	}
+
+	doc, err := os.ReadFile(filepath.Join(dir, "Example-start.md"))
+	if err != nil || strings.Contains(string(doc), "## Flags") ||
+		!strings.Contains(string(doc), "## Global flags") || !strings.Contains(string(doc), "--verbose") {
+		t.Errorf("Markdown doc should list global flags under Global flags only:\n%s", doc)
+	}
+
+	index, err = os.ReadFile(filepath.Join(dir, "Example.md"))
+	if err != nil || !strings.Contains(string(index), "--verbose") {
+		t.Errorf("Markdown index should list global flags")
+	}
+}
@@ -0,0 +1,102 @@
+package broccli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors that are returned or wrapped by the package.  Use errors.Is to check for them.
+var (
+	ErrFileNotExist       = errors.New("file does not exist")
+	ErrFileInfo           = errors.New("file cannot be opened for stat info")
+	ErrFileExist          = errors.New("file already exists")
+	ErrFileNotRegularFile = errors.New("file is not a regular file")
+	ErrFileNotDirectory   = errors.New("file is not a directory")
+	ErrFileOpen           = errors.New("file cannot be opened")
+	ErrFileNotValidJSON   = errors.New("file is not a valid JSON")
+	ErrParamValueMissing  = errors.New("param value missing")
+	ErrParamValueInvalid  = errors.New("param value invalid")
+	ErrParamTypeInvalid   = errors.New("param type invalid")
+	ErrParamNotFound      = errors.New("param not found")
+	ErrParamTypeMismatch  = errors.New("param type mismatch")
+	ErrFlagConflict       = errors.New("both alias and name passed")
+	ErrFlagUnknown        = errors.New("unknown flag")
+	ErrFlagGroupInvalid   = errors.New("invalid flags")
+	ErrConfigFileInvalid  = errors.New("config file invalid")
+	ErrShellUnsupported   = errors.New("shell not supported")
+	ErrCommandInvalid     = errors.New("invalid command")
+	ErrArgUnexpected      = errors.New("unexpected argument")
+	ErrArgCountInvalid    = errors.New("invalid number of values")
+	ErrHelp               = errors.New("help requested")
+)
+
+// ParamError is returned when flag, arg or environment variable is not valid.  It wraps the error that describes
+// the reason, eg. ErrParamValueMissing or ErrFileNotExist.
+type ParamError struct {
+	// Kind is one of ParamFlag, ParamArg or ParamEnvVar.
+	Kind int8
+	// Name is the name of flag, arg or environment variable.
+	Name string
+	// Value is the value that failed the validation.
+	Value string
+	// Source describes where the value was taken from, eg. 'env var TOKEN'.  It is empty for command line.
+	Source string
+	// Err is the reason of the failure.
+	Err error
+
+	label string
+}
+
+func (e *ParamError) Error() string {
+	label := e.label
+	if label == "" {
+		label = e.Name
+	}
+
+	message := fmt.Sprintf("%s %s", paramTypeName(e.Kind), label)
+	if e.Source != "" {
+		message += fmt.Sprintf(" (from %s)", e.Source)
+	}
+
+	return message + ": " + e.Err.Error()
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+func paramTypeName(t int8) string {
+	if t == ParamArg {
+		return "Argument"
+	}
+
+	if t == ParamEnvVar {
+		return "Env var"
+	}
+
+	return "Flag"
+}
+
+func errCommandMissingIn(name string) error {
+	return fmt.Errorf("%w: subcommand of %s is missing", ErrCommandInvalid, name)
+}
+
+func errCommandInvalidWithName(name string, commands map[string]*Command) error {
+	return fmt.Errorf("%w: %s%s", ErrCommandInvalid, name, suggestion(name, commandCandidates(commands), ""))
+}
+
+func errFlagConflictWithNames(alias string, name string) error {
+	return fmt.Errorf("%w: -%s and --%s", ErrFlagConflict, alias, name)
+}
+
+func errArgUnexpectedWithValue(value string) error {
+	return fmt.Errorf("%w: %s", ErrArgUnexpected, value)
+}
+
+func errArgCountInvalidWithRange(count, minCount, maxCount int) error {
+	if maxCount > 0 && count > maxCount {
+		return fmt.Errorf("%w: %d passed, at most %d allowed", ErrArgCountInvalid, count, maxCount)
+	}
+
+	return fmt.Errorf("%w: %d passed, at least %d required", ErrArgCountInvalid, count, minCount)
+}
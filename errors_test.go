@@ -0,0 +1,169 @@
+package broccli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestParseErrors tests errors returned from Parse.
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	cli := newTestCLI(WithLookupEnv(func(name string) (string, bool) {
+		if name == "WORDS_PATH" {
+			return "/non-existing/words.txt", true
+		}
+
+		return "", false
+	}))
+	cmd1 := cli.Command("cmd", "Does something", func(_ context.Context, _ *Broccli) int {
+		return 2
+	})
+	cmd1.Flag("text", "t", "Text", "Text to check", TypeString, IsRequired)
+	cmd1.Flag("words", "w", "PATH", "Words file", TypePathFile, IsExistent, FromEnv("WORDS_PATH"))
+	cmd1.Arg("count", "COUNT", "Count", TypeInt, 0)
+
+	_, err := cli.Parse([]string{"test"})
+	if !errors.Is(err, ErrHelp) {
+		t.Errorf("Parse() should have returned ErrHelp instead of %v", err)
+	}
+
+	cmd, err := cli.Parse([]string{"test", "cmd", "--help"})
+	if !errors.Is(err, ErrHelp) || cmd != cmd1 {
+		t.Errorf("Parse() should have returned ErrHelp with the command instead of %v", err)
+	}
+
+	_, err = cli.Parse([]string{"test", "wrongcmd"})
+	if !errors.Is(err, ErrCommandInvalid) {
+		t.Errorf("Parse() should have returned ErrCommandInvalid instead of %v", err)
+	}
+
+	var paramErr *ParamError
+
+	_, err = cli.Parse([]string{"test", "cmd"})
+	if !errors.As(err, &paramErr) || !errors.Is(err, ErrParamValueMissing) {
+		t.Fatalf("Parse() should have returned ParamError with ErrParamValueMissing instead of %v", err)
+	}
+
+	if paramErr.Kind != ParamFlag || paramErr.Name != "text" {
+		t.Errorf("ParamError should be for flag text instead of %d %s", paramErr.Kind, paramErr.Name)
+	}
+
+	_, err = cli.Parse([]string{"test", "cmd", "-t", "x"})
+	if !errors.As(err, &paramErr) || !errors.Is(err, ErrFileNotExist) {
+		t.Fatalf("Parse() should have returned ParamError with ErrFileNotExist instead of %v", err)
+	}
+
+	if paramErr.Source != "env var WORDS_PATH" || paramErr.Value != "/non-existing/words.txt" {
+		t.Errorf("ParamError has invalid source or value: %s %s", paramErr.Source, paramErr.Value)
+	}
+
+	_, err = cli.Parse([]string{"test", "cmd", "-t", "x", "-w", "errors_test.go", "abc"})
+	if !errors.As(err, &paramErr) || paramErr.Kind != ParamArg || paramErr.Value != "abc" {
+		t.Errorf("Parse() should have returned ParamError for arg instead of %v", err)
+	}
+
+	if err.Error() != "Argument COUNT: param value invalid" {
+		t.Errorf("ParamError message is invalid: %s", err.Error())
+	}
+}
+
+// TestExecute tests running the command with Execute.
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	cli := newTestCLI()
+	cmd1 := cli.Command("cmd", "Does something", func(_ context.Context, c *Broccli) int {
+		if c.Flag("text") == "x" {
+			return 2
+		}
+
+		return 3
+	})
+	cmd1.Flag("text", "t", "Text", "Text to check", TypeString, IsRequired)
+
+	got, err := cli.Execute(context.Background(), []string{"test", "cmd", "-t", "x"})
+	if err != nil || got != 2 {
+		t.Errorf("Execute() should have returned 2 without error instead of %d %v", got, err)
+	}
+
+	_, err = cli.Execute(context.Background(), []string{"test", "cmd", "-t", "x", "--text", "y"})
+	if !errors.Is(err, ErrFlagConflict) {
+		t.Errorf("Execute() should have returned ErrFlagConflict instead of %v", err)
+	}
+}
+
+// TestParseAllErrors tests collecting all the validation errors.
+func TestParseAllErrors(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+
+	cli := newTestCLI(WithAllErrors(), WithErrorOutput(&stderr), WithLookupEnv(func(_ string) (string, bool) {
+		return "", false
+	}))
+	cli.Env("APP_TOKEN", "Token")
+	cmd1 := cli.Command("cmd", "Does something", func(_ context.Context, _ *Broccli) int {
+		return 2
+	})
+	cmd1.Flag("text", "t", "Text", "Text to check", TypeString, IsRequired)
+	cmd1.Flag("count", "c", "INT", "Count", TypeInt, IsRequired)
+	cmd1.Flag("level", "l", "INT", "Level", TypeInt, 0)
+	cmd1.Arg("name", "NAME", "Name", TypeAlphanumeric, IsRequired)
+
+	cmd, err := cli.Parse([]string{"test", "cmd", "-l", "x"})
+	if cmd != cmd1 {
+		t.Errorf("Parse() should have returned the command")
+	}
+
+	errs := flattenErrors(err)
+	if len(errs) != 5 {
+		t.Fatalf("Parse() should have returned 5 errors instead of %d: %v", len(errs), err)
+	}
+
+	names := []string{}
+
+	for _, e := range errs {
+		var paramErr *ParamError
+		if !errors.As(e, &paramErr) {
+			t.Fatalf("Parse() should have returned ParamError instead of %v", e)
+		}
+
+		names = append(names, paramErr.Name)
+	}
+
+	if strings.Join(names, ",") != "APP_TOKEN,count,level,text,name" {
+		t.Errorf("Parse() returned errors in invalid order: %v", names)
+	}
+
+	got := cli.RunArgs(context.Background(), []string{"test", "cmd", "-l", "x"})
+	if got != 1 {
+		t.Errorf("CLI.Run() should have returned 1 instead of %d", got)
+	}
+
+	if !strings.HasPrefix(stderr.String(), "ERROR: 5 validation errors:\n  - Env var APP_TOKEN: ") {
+		t.Errorf("CLI.Run() printed invalid errors: %s", stderr.String())
+	}
+}
+
+// TestParseAllErrorsValueMissing tests that flag passed without a value is reported once.
+func TestParseAllErrorsValueMissing(t *testing.T) {
+	t.Parallel()
+
+	cli := newTestCLI(WithAllErrors())
+	cmd := cli.Command("cmd", "Does something", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd.Flag("name", "n", "NAME", "Name", TypeString, IsRequired)
+	cmd.Flag("level", "l", "INT", "Level", TypeInt, 0)
+
+	_, err := cli.Parse([]string{"test", "cmd", "--level", "x", "--name"})
+
+	errs := flattenErrors(err)
+	if len(errs) != 2 || !errors.Is(errs[0], ErrParamValueMissing) || !errors.Is(errs[1], ErrParamValueInvalid) {
+		t.Errorf("Parse() should have returned missing value of name and invalid level: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package broccli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of flag groups.
+const (
+	groupExactlyOneOf = iota
+	groupAtMostOneOf
+	groupAllOrNone
+	groupRequires
+)
+
+// flagGroup is a constraint on flags of a command.  For groupRequires, first name is the flag that requires the rest.
+type flagGroup struct {
+	kind  int
+	names []string
+}
+
+// ExactlyOneOf requires exactly one of the flags to be passed.  Flags must be added to the command (or its parents)
+// before calling the method, otherwise it panics.  Flags set with a default value are not treated as passed.
+func (c *Command) ExactlyOneOf(names ...string) {
+	c.addFlagGroup(groupExactlyOneOf, names)
+}
+
+// AtMostOneOf allows only one of the flags to be passed.  It works the same as ExactlyOneOf otherwise.
+func (c *Command) AtMostOneOf(names ...string) {
+	c.addFlagGroup(groupAtMostOneOf, names)
+}
+
+// AllOrNone requires the flags to be passed together or not at all.  It works the same as ExactlyOneOf otherwise.
+func (c *Command) AllOrNone(names ...string) {
+	c.addFlagGroup(groupAllOrNone, names)
+}
+
+// Requires makes flag require other flags when it is passed.  It works the same as ExactlyOneOf otherwise.
+func (c *Command) Requires(name string, required ...string) {
+	c.addFlagGroup(groupRequires, append([]string{name}, required...))
+}
+
+func (c *Command) addFlagGroup(kind int, names []string) {
+	flags := c.allFlags()
+	for _, name := range names {
+		if _, ok := flags[name]; !ok {
+			panic(fmt.Sprintf("flag %s in a group of command %s does not exist", name, c.name))
+		}
+	}
+
+	c.groups = append(c.groups, flagGroup{kind: kind, names: names})
+}
+
+// helpLine returns description of the group that is shown on the help screen.
+func (g flagGroup) helpLine() string {
+	switch g.kind {
+	case groupExactlyOneOf:
+		return "Exactly one of " + flagList(g.names)
+	case groupAtMostOneOf:
+		return "At most one of " + flagList(g.names)
+	case groupAllOrNone:
+		return "All or none of " + flagList(g.names)
+	default:
+		return flagList(g.names[:1]) + " requires " + flagList(g.names[1:])
+	}
+}
+
+// check returns error when the group constraint is not met.  Passed contains names of flags that were passed.
+func (g flagGroup) check(passed map[string]bool) error {
+	passedNames := []string{}
+	missingNames := []string{}
+
+	for _, name := range g.names {
+		if passed[name] {
+			passedNames = append(passedNames, name)
+		} else {
+			missingNames = append(missingNames, name)
+		}
+	}
+
+	switch {
+	case g.kind == groupExactlyOneOf && len(passedNames) == 0:
+		return fmt.Errorf("%w: one of %s is required", ErrFlagGroupInvalid, flagList(g.names))
+	case (g.kind == groupExactlyOneOf || g.kind == groupAtMostOneOf) && len(passedNames) > 1:
+		return fmt.Errorf("%w: only one of %s can be passed", ErrFlagGroupInvalid, flagList(passedNames))
+	case g.kind == groupAllOrNone && len(passedNames) > 0 && len(missingNames) > 0:
+		return fmt.Errorf("%w: %s must be passed together, missing %s", ErrFlagGroupInvalid,
+			flagList(g.names), flagList(missingNames))
+	case g.kind == groupRequires && passed[g.names[0]] && len(missingNames) > 0:
+		return fmt.Errorf("%w: %s requires %s", ErrFlagGroupInvalid, flagList(g.names[:1]), flagList(missingNames))
+	default:
+		return nil
+	}
+}
+
+// checkFlagGroups checks constraints of groups of the command and its parents.
+func (c *Broccli) checkFlagGroups(cmd *Command) error {
+	errs := []error{}
+
+	for _, group := range cmd.allFlagGroups() {
+		err := group.check(c.passedFlags)
+		if err != nil && c.stopOnError(&errs, err) {
+			return err
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func flagList(names []string) string {
+	return "--" + strings.Join(names, ", --")
+}
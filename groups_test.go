@@ -0,0 +1,66 @@
+package broccli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestFlagGroups tests constraints on groups of flags.
+func TestFlagGroups(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	cmd := c.Command("deploy", "Deploys the app", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd.Flag("tag", "", "TAG", "Image tag", TypeString, 0)
+	cmd.Flag("digest", "", "DIGEST", "Image digest", TypeString, 0)
+	cmd.Flag("json", "", "", "JSON output", TypeBool, 0)
+	cmd.Flag("yaml", "", "", "YAML output", TypeBool, 0)
+	cmd.Flag("user", "", "USER", "User", TypeString, 0)
+	cmd.Flag("password", "", "PASSWORD", "Password", TypeString, 0)
+	cmd.Flag("cert", "", "CERT", "Certificate", TypeString, 0)
+	cmd.Flag("key", "", "KEY", "Key", TypeString, 0)
+	cmd.Flag("region", "", "REGION", "Region", TypeString, 0, WithDefault("eu"))
+	cmd.ExactlyOneOf("tag", "digest")
+	cmd.AtMostOneOf("json", "yaml", "region")
+	cmd.AllOrNone("user", "password")
+	cmd.Requires("cert", "key")
+
+	testCases := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"--tag", "v1", "--region", "eu"}, ""},
+		{[]string{"--digest", "sha", "--json", "--user", "u", "--password", "p", "--cert", "c", "--key", "k"}, ""},
+		{[]string{}, "one of --tag, --digest is required"},
+		{[]string{"--tag", "v1", "--digest", "sha"}, "only one of --tag, --digest can be passed"},
+		{[]string{"--tag", "v1", "--json", "--yaml"}, "only one of --json, --yaml can be passed"},
+		{[]string{"--tag", "v1", "--json", "--region", "us"}, "only one of --json, --region can be passed"},
+		{[]string{"--tag", "v1", "--password", "p"}, "--user, --password must be passed together, missing --user"},
+		{[]string{"--tag", "v1", "--cert", "c"}, "--cert requires --key"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := c.Parse(append([]string{"test", "deploy"}, testCase.args...))
+		if testCase.message == "" {
+			if err != nil {
+				t.Errorf("Parse() with %v returned error: %s", testCase.args, err.Error())
+			}
+
+			continue
+		}
+
+		if !errors.Is(err, ErrFlagGroupInvalid) || !strings.HasSuffix(err.Error(), testCase.message) {
+			t.Errorf("Parse() with %v should have returned '%s' instead of %v", testCase.args, testCase.message, err)
+		}
+	}
+
+	help := cmd.helpMessage("test")
+	if !strings.Contains(help, "Flag constraints:\n  Exactly one of --tag, --digest\n") ||
+		!strings.Contains(help, "  --cert requires --key\n") {
+		t.Errorf("Help message does not contain flag constraints: %s", help)
+	}
+}
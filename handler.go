@@ -0,0 +1,56 @@
+package broccli
+
+import (
+	"context"
+	"fmt"
+)
+
+// CommandFor adds a command with a handler that takes a value of T instead of Broccli.  Flags and args of the command
+// are defined from fields of T, which must be a struct, and T is filled with their values before the handler is
+// called, see Command.Bind for the tags.  Error returned from the handler is printed and turned into exit code
+// with the function set by WithExitCodeMapper.
+func CommandFor[T any](
+	cli *Broccli,
+	name, usage string,
+	handler func(ctx context.Context, opts T) error,
+	opts ...CommandOption,
+) *Command {
+	var target T
+
+	cmd := cli.Command(name, usage, typedHandler(&target, handler), opts...)
+	cmd.Bind(&target)
+
+	return cmd
+}
+
+// SubcommandFor works the same as CommandFor but adds a subcommand to parent.
+func SubcommandFor[T any](
+	parent *Command,
+	name, usage string,
+	handler func(ctx context.Context, opts T) error,
+	opts ...CommandOption,
+) *Command {
+	var target T
+
+	cmd := parent.Command(name, usage, typedHandler(&target, handler), opts...)
+	cmd.Bind(&target)
+
+	return cmd
+}
+
+// typedHandler wraps handler that takes a value of T so that it can be used as a command handler.
+func typedHandler[T any](
+	target *T,
+	handler func(ctx context.Context, opts T) error,
+) func(ctx context.Context, cli *Broccli) int {
+	return func(ctx context.Context, cli *Broccli) int {
+		err := handler(ctx, *target)
+		if err != nil {
+			fmt.Fprintf(cli.Stderr(), "ERROR: %s\n", err.Error())
+
+			return cli.options.exitCode(err)
+		}
+
+		return 0
+	}
+}
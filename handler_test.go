@@ -0,0 +1,73 @@
+package broccli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+var errHandlerTest = errors.New("not allowed")
+
+type handlerTestOptions struct {
+	Name  string `flag:"name" alias:"n" usage:"Name" required:"true"`
+	Count int    `arg:"count" usage:"Count"`
+}
+
+// TestCommandFor tests commands with typed handlers.
+func TestCommandFor(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+
+	cli := newTestCLI(WithErrorOutput(&stderr), WithExitCodeMapper(func(err error) int {
+		if errors.Is(err, errHandlerTest) {
+			return 3
+		}
+
+		var paramErr *ParamError
+		if errors.As(err, &paramErr) {
+			return 2
+		}
+
+		return 1
+	}))
+
+	var got handlerTestOptions
+
+	CommandFor(cli, "greet", "Greets", func(_ context.Context, opts handlerTestOptions) error {
+		got = opts
+		if opts.Name == "root" {
+			return errHandlerTest
+		}
+
+		return nil
+	})
+
+	group := cli.Command("user", "Manages users", nil)
+	SubcommandFor(group, "add", "Adds user", func(_ context.Context, opts handlerTestOptions) error {
+		got = opts
+
+		return nil
+	})
+
+	exitCode := cli.RunArgs(context.Background(), []string{"test", "greet", "-n", "john", "4"})
+	if exitCode != 0 || got.Name != "john" || got.Count != 4 {
+		t.Errorf("CLI.Run() should have returned 0 and passed the values instead of %d %+v", exitCode, got)
+	}
+
+	exitCode = cli.RunArgs(context.Background(), []string{"test", "greet", "-n", "root"})
+	if exitCode != 3 || stderr.String() != "ERROR: not allowed\n" {
+		t.Errorf("CLI.Run() should have returned 3 and printed the error instead of %d %q", exitCode, stderr.String())
+	}
+
+	exitCode = cli.RunArgs(context.Background(), []string{"test", "greet"})
+	if exitCode != 2 {
+		t.Errorf("CLI.Run() should have returned 2 for invalid flag instead of %d", exitCode)
+	}
+
+	exitCode = cli.RunArgs(context.Background(), []string{"test", "user", "add", "--name", "jane"})
+	if exitCode != 0 || got.Name != "jane" || got.Count != 0 {
+		t.Errorf("CLI.Run() should have returned 0 and passed the values instead of %d %+v", exitCode, got)
+	}
+}
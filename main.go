@@ -27,6 +27,22 @@ const (
 	TypeAlphanumeric
 	// TypePathFile requires param to be a path to a file.
 	TypePathFile
+	// TypeDuration requires param to be a duration, eg. 30s or 1h30m.
+	TypeDuration
+	// TypeTime requires param to be a date or time in RFC 3339 format, eg. 2024-01-01 or 2024-01-01T10:00:00Z.
+	TypeTime
+	// TypeURL requires param to be an absolute URL, eg. https://example.com/path.
+	TypeURL
+	// TypeIP requires param to be an IPv4 or IPv6 address.
+	TypeIP
+	// TypeCIDR requires param to be an IP network in CIDR notation, eg. 10.0.0.0/8.
+	TypeCIDR
+	// TypeSize requires param to be a size in bytes with optional unit, eg. 512MiB or 10MB.
+	TypeSize
+	// TypeRegexp requires param to be a valid regular expression.
+	TypeRegexp
+	// TypeCustom requires param to be a valid value of ValueType set with WithType option.
+	TypeCustom
 )
 
 // Validation.
@@ -60,6 +76,10 @@ const (
 	SeparatorColon
 	// SeparatorSemiColon works with AllowMultipleValues and sets semi-colon to be the value separator.
 	SeparatorSemiColon
+
+	// AllowRepeated allows flag to be passed more than once, eg. '--tag a --tag b'.  Each occurrence is validated
+	// separately and it can contain multiple values when used with AllowMultipleValues.
+	AllowRepeated
 )
 
 const (
@@ -69,7 +89,3 @@ const (
 	tabWriterPadding            = 8
 	tabWriterPadChar            = '\t'
 )
-
-const (
-	maxArgs = 10
-)
@@ -4,50 +4,40 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
-)
-
-var (
-	errFileNotExist       = errors.New("file does not exist")
-	errFileInfo           = errors.New("file cannot be opened for stat info")
-	errFileExist          = errors.New("file already exists")
-	errFileNotRegularFile = errors.New("file is not a regular file")
-	errFileNotDirectory   = errors.New("file is not a directory")
-	errFileOpen           = errors.New("file cannot be opened")
-	errFileNotValidJSON   = errors.New("file is not a valid JSON")
-	errParamValueMissing  = errors.New("param value missing")
-	errParamValueInvalid  = errors.New("param value invalid")
-	errParamTypeInvalid   = errors.New("param type invalid")
+	"strconv"
+	"strings"
 )
 
 func errFileNotExistInPath(path string) error {
-	return fmt.Errorf("%w: %s", errFileNotExist, path)
+	return fmt.Errorf("%w: %s", ErrFileNotExist, path)
 }
 
 func errFileInfoInPath(path string) error {
-	return fmt.Errorf("%w: %s", errFileInfo, path)
+	return fmt.Errorf("%w: %s", ErrFileInfo, path)
 }
 
 func errFileExistInPath(path string) error {
-	return fmt.Errorf("%w: %s", errFileExist, path)
+	return fmt.Errorf("%w: %s", ErrFileExist, path)
 }
 
 func errFileNotRegularFileInPath(path string) error {
-	return fmt.Errorf("%w: %s", errFileNotRegularFile, path)
+	return fmt.Errorf("%w: %s", ErrFileNotRegularFile, path)
 }
 
 func errFileNotDirectoryInPath(path string) error {
-	return fmt.Errorf("%w: %s", errFileNotDirectory, path)
+	return fmt.Errorf("%w: %s", ErrFileNotDirectory, path)
 }
 
 func errFileOpenInPath(reason string, path string) error {
-	return fmt.Errorf("%s %w: %s", reason, errFileOpen, path)
+	return fmt.Errorf("%s %w: %s", reason, ErrFileOpen, path)
 }
 
 func errFileNotValidJSONInPath(path string) error {
-	return fmt.Errorf("%w: %s", errFileNotValidJSON, path)
+	return fmt.Errorf("%w: %s", ErrFileNotValidJSON, path)
 }
 
 // param represends a value and it is used for flags, args and environment variables.
@@ -63,6 +53,8 @@ type param struct {
 	valueType        int64
 	flags            int64
 	options          paramOptions
+	// fileFlag is a flag with path to the file that value of sensitive flag can be read from
+	fileFlag *param
 }
 
 // helpLine returns param usage info that is used when printing help.
@@ -74,11 +66,136 @@ func (p *param) helpLine() string {
 		usageLine += fmt.Sprintf(" -%s,\t", p.alias)
 	}
 
-	usageLine += fmt.Sprintf(" --%s %s \t%s\n", p.name, p.valuePlaceholder, p.usage)
+	usageLine += fmt.Sprintf(" --%s %s \t%s\n", p.name, p.valuePlaceholder, p.description())
 
 	return usageLine
 }
 
+// description returns param usage together with additional information such as its default value.
+func (p *param) description() string {
+	description := p.usage
+	if len(p.options.envVars) > 0 {
+		description += " (env: $" + strings.Join(p.options.envVars, ", $") + ")"
+	}
+
+	if len(p.options.choices) > 0 {
+		description += " (one of: " + strings.Join(p.options.choices, ", ") + ")"
+	}
+
+	if p.options.minValue != nil {
+		description += " (min: " + formatBound(*p.options.minValue) + ")"
+	}
+
+	if p.options.maxValue != nil {
+		description += " (max: " + formatBound(*p.options.maxValue) + ")"
+	}
+
+	if p.options.deprecated {
+		description += deprecationNote(p.options.deprecation)
+	}
+
+	if p.flags&AllowRepeated > 0 {
+		description += " (can be repeated)"
+	}
+
+	if p.options.defaultValue != "" {
+		description += fmt.Sprintf(" (default: %s)", p.displayValue(p.options.defaultValue))
+	}
+
+	return description
+}
+
+// mustBeValid panics when param of TypeCustom does not have a ValueType or when its default value is not valid.
+// Placeholder of param without one is taken from its ValueType.
+func (p *param) mustBeValid() {
+	if p.valueType == TypeCustom && p.options.valueType == nil {
+		panic(fmt.Sprintf("param %s of TypeCustom requires WithType option", p.name))
+	}
+
+	if p.options.valueType != nil && p.valuePlaceholder == "" {
+		p.valuePlaceholder = p.options.valueType.Placeholder()
+	}
+
+	p.mustValidateBounds()
+	p.mustValidateDefault()
+}
+
+// mustValidateBounds panics when Min, Max or BitSize is set on a param that is not numeric, or when they are invalid.
+func (p *param) mustValidateBounds() {
+	hasBounds := p.options.minValue != nil || p.options.maxValue != nil
+	if hasBounds && p.valueType != TypeInt && p.valueType != TypeFloat {
+		panic(fmt.Sprintf("param %s with Min or Max must be of TypeInt or TypeFloat", p.name))
+	}
+
+	if p.options.bitSize != 0 && p.valueType != TypeInt {
+		panic(fmt.Sprintf("param %s with BitSize must be of TypeInt", p.name))
+	}
+
+	switch p.options.bitSize {
+	case 0, 8, 16, 32, 64:
+	default:
+		panic(fmt.Sprintf("invalid bit size of %s: %d", p.name, p.options.bitSize))
+	}
+
+	if p.options.minValue != nil && p.options.maxValue != nil &&
+		*p.options.minValue > *p.options.maxValue {
+		panic(fmt.Sprintf("invalid bounds of %s: Min is greater than Max", p.name))
+	}
+}
+
+// mustValidateDefault panics when default value of param is not valid.  File path checks are skipped as the file
+// might not exist when the param is defined.
+func (p *param) mustValidateDefault() {
+	if p.options.defaultValue == "" || p.valueType == TypePathFile {
+		return
+	}
+
+	err := p.validateValue(p.options.defaultValue)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default value of %s: %s", p.name, err.Error()))
+	}
+}
+
+// valueOrDefault returns default value of param when value is empty.
+func (p *param) valueOrDefault(value string) string {
+	if value == "" {
+		return p.options.defaultValue
+	}
+
+	return value
+}
+
+// argPlaceholder returns placeholder of arg that is shown in the usage line, eg. FILES... for variadic arg.
+func (p *param) argPlaceholder() string {
+	if p.options.variadic {
+		return p.valuePlaceholder + "..."
+	}
+
+	return p.valuePlaceholder
+}
+
+// minArgCount returns minimal number of values of variadic arg.
+func (p *param) minArgCount() int {
+	if p.flags&IsRequired > 0 && p.options.minCount < 1 {
+		return 1
+	}
+
+	return p.options.minCount
+}
+
+// separator returns string that separates values when param allows multiple values.
+func (p *param) separator() string {
+	if p.flags&SeparatorColon > 0 {
+		return ":"
+	}
+
+	if p.flags&SeparatorSemiColon > 0 {
+		return ";"
+	}
+
+	return ","
+}
+
 func (p *param) validatePathFile(path string) error {
 	fileInfo, err := os.Stat(path)
 	if err != nil {
@@ -119,11 +236,137 @@ func (p *param) validatePathFile(path string) error {
 	return nil
 }
 
-//nolint:funlen
+// validateNumber parses value of TypeInt or TypeFloat param and checks if it is within the bounds.
+func (p *param) validateNumber(value string) error {
+	var number float64
+
+	if p.valueType == TypeInt {
+		bitSize := p.options.bitSize
+		if bitSize == 0 {
+			bitSize = 64
+		}
+
+		i, err := strconv.ParseInt(value, 10, bitSize)
+		if errors.Is(err, strconv.ErrRange) {
+			return fmt.Errorf("%w: %s is out of range of %d-bit integer", ErrParamValueInvalid, value, bitSize)
+		}
+
+		if err != nil {
+			return ErrParamValueInvalid
+		}
+
+		number = float64(i)
+	} else {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return ErrParamValueInvalid
+		}
+
+		number = f
+	}
+
+	if p.options.minValue != nil && number < *p.options.minValue {
+		return fmt.Errorf("%w: must be at least %s", ErrParamValueInvalid, formatBound(*p.options.minValue))
+	}
+
+	if p.options.maxValue != nil && number > *p.options.maxValue {
+		return fmt.Errorf("%w: must be at most %s", ErrParamValueInvalid, formatBound(*p.options.maxValue))
+	}
+
+	return nil
+}
+
+// formatBound returns bound set with Min or Max without trailing zeros.
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'f', -1, 64)
+}
+
+// validateChoices returns error when value is not one of the choices set with OneOf.
+func (p *param) validateChoices(paramValue string) error {
+	values := []string{paramValue}
+	if p.flags&AllowMultipleValues > 0 {
+		values = strings.Split(paramValue, p.separator())
+	}
+
+	for _, value := range values {
+		if p.choice(value) == "" {
+			return fmt.Errorf(
+				"%w: must be one of %s%s",
+				ErrParamValueInvalid,
+				strings.Join(p.options.choices, ", "),
+				suggestion(value, p.options.choices, ""),
+			)
+		}
+	}
+
+	return nil
+}
+
+// choice returns the choice that value matches, or empty string when there is no such choice.
+func (p *param) choice(value string) string {
+	for _, choice := range p.options.choices {
+		if choice == value || (p.options.ignoreCase && strings.EqualFold(choice, value)) {
+			return choice
+		}
+	}
+
+	return ""
+}
+
+// canonicalValue returns value with each of its parts replaced with the choice it matches.  It is needed only when
+// choices are compared case-insensitively.
+func (p *param) canonicalValue(paramValue string) string {
+	if !p.options.ignoreCase || len(p.options.choices) == 0 || paramValue == "" {
+		return paramValue
+	}
+
+	values := []string{paramValue}
+	if p.flags&AllowMultipleValues > 0 {
+		values = strings.Split(paramValue, p.separator())
+	}
+
+	for i, value := range values {
+		if choice := p.choice(value); choice != "" {
+			values[i] = choice
+		}
+	}
+
+	return strings.Join(values, p.separator())
+}
+
+// validateValue checks value of param with its type and then with validators set with WithValidator.  Value of
+// sensitive param is masked in the returned error.
 func (p *param) validateValue(paramValue string) error {
+	err := p.validateType(paramValue)
+	if err != nil || paramValue == "" {
+		return p.maskError(err, paramValue)
+	}
+
+	for _, validator := range p.options.validators {
+		for _, value := range p.stringValues(paramValue) {
+			err = validator(value)
+			if err != nil {
+				return p.maskError(errParamValueInvalidWithCause(err), paramValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+//nolint:funlen
+func (p *param) validateType(paramValue string) error {
 	// empty, for every time except bool
 	if p.valueType != TypeBool && (p.flags&IsRequired > 0) && paramValue == "" {
-		return errParamValueMissing
+		return ErrParamValueMissing
+	}
+
+	// choices are checked in addition to the type
+	if len(p.options.choices) > 0 && paramValue != "" {
+		err := p.validateChoices(paramValue)
+		if err != nil {
+			return err
+		}
 	}
 
 	// string does not need any additional checks apart from the above one
@@ -136,6 +379,15 @@ func (p *param) validateValue(paramValue string) error {
 		return nil
 	}
 
+	if p.valueType == TypeBool {
+		_, err := strconv.ParseBool(paramValue)
+		if err != nil {
+			return ErrParamValueInvalid
+		}
+
+		return nil
+	}
+
 	// if flag is a file (regular file, directory, ...)
 	if p.valueType == TypePathFile {
 		errValidatePathFile := p.validatePathFile(paramValue)
@@ -146,18 +398,50 @@ func (p *param) validateValue(paramValue string) error {
 		return nil
 	}
 
-	// int, float, alphanumeric - single or many, separated by various chars
+	// custom type set with WithType - single or many, separated by various chars
+	if p.valueType == TypeCustom {
+		for _, value := range p.stringValues(paramValue) {
+			_, err := parseCustomValue(p.options.valueType, value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// duration, time, URL etc. - single or many, separated by various chars
+	if isTypedValue(p.valueType) {
+		for _, value := range p.stringValues(paramValue) {
+			_, err := parseTypedValue(p.valueType, value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// int, float - single or many, separated by various chars
+	if p.valueType == TypeInt || p.valueType == TypeFloat {
+		for _, value := range p.stringValues(paramValue) {
+			err := p.validateNumber(value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// alphanumeric - single or many, separated by various chars
 	var (
 		reType  string
 		reValue string
 	)
-	// set regexp part just for the type (eg. int, float, anum)
+	// set regexp part just for the type (eg. anum)
 
 	switch p.valueType {
-	case TypeInt:
-		reType = "[0-9]+"
-	case TypeFloat:
-		reType = "[0-9]{1,16}\\.[0-9]{1,16}"
 	case TypeAlphanumeric:
 		reExtraChars := ""
 		if p.flags&AllowUnderscore > 0 {
@@ -174,29 +458,19 @@ func (p *param) validateValue(paramValue string) error {
 
 		reType = fmt.Sprintf("[0-9a-zA-Z%s]+", reExtraChars)
 	default:
-		return errParamTypeInvalid
+		return ErrParamTypeInvalid
 	}
 
 	// create the final regexp depending on if single or many values are allowed
 	if p.flags&AllowMultipleValues > 0 {
-		var delimeter string
-		//nolint:gocritic
-		if p.flags&SeparatorColon > 0 {
-			delimeter = ":"
-		} else if p.flags&SeparatorSemiColon > 0 {
-			delimeter = ";"
-		} else {
-			delimeter = ","
-		}
-
-		reValue = "^" + reType + "(" + delimeter + reType + ")*$"
+		reValue = "^" + reType + "(" + regexp.QuoteMeta(p.separator()) + reType + ")*$"
 	} else {
 		reValue = "^" + reType + "$"
 	}
 
 	m, err := regexp.MatchString(reValue, paramValue)
 	if err != nil || !m {
-		return errParamValueInvalid
+		return ErrParamValueInvalid
 	}
 
 	return nil
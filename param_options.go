@@ -1,7 +1,24 @@
 package broccli
 
 type paramOptions struct {
-	onTrue func(command *Command)
+	onTrue       func(command *Command)
+	defaultValue string
+	envVars      []string
+	completion   func(current string) []string
+	variadic     bool
+	choices      []string
+	ignoreCase   bool
+	minCount     int
+	maxCount     int
+	hideInput    bool
+	sensitive    bool
+	deprecated   bool
+	deprecation  string
+	valueType    ValueType
+	validators   []Validator
+	minValue     *float64
+	maxValue     *float64
+	bitSize      int
 }
 
 // ParamOption defines an optional configuration function for args and flags, intended for specific use cases.
@@ -14,3 +31,118 @@ func OnTrue(fn func(command *Command)) ParamOption {
 		opts.onTrue = fn
 	}
 }
+
+// WithDefault sets a value that is used when param is not passed or is empty.  The value is validated when param is
+// defined and function panics when it is not valid.
+func WithDefault(value string) ParamOption {
+	return func(opts *paramOptions) {
+		opts.defaultValue = value
+	}
+}
+
+// FromEnv binds flag to environment variables.  When flag is not passed, its value is taken from the first
+// non-empty environment variable in names.  Value passed in the command line takes precedence over the environment
+// variables, and these take precedence over the default value.
+func FromEnv(names ...string) ParamOption {
+	return func(opts *paramOptions) {
+		opts.envVars = names
+	}
+}
+
+// WithCompletion attaches a function that returns candidates for shell completion of the param value.  Current is
+// the value typed so far.  Returned candidates not starting with current are skipped.
+func WithCompletion(fn func(current string) []string) ParamOption {
+	return func(opts *paramOptions) {
+		opts.completion = fn
+	}
+}
+
+// Variadic makes arg capture all the remaining args, eg. FILES...  It can be used only with the last arg of a command.
+// Each value is validated separately.  MinCount and maxCount limit the number of values, and 0 means no limit.
+// When arg has IsRequired, at least one value is required.
+func Variadic(minCount, maxCount int) ParamOption {
+	return func(opts *paramOptions) {
+		opts.variadic = true
+		opts.minCount = minCount
+		opts.maxCount = maxCount
+	}
+}
+
+// OneOf requires value of the param to be one of the choices.  Choices are listed on the help screen and used for
+// shell completion.  With AllowMultipleValues, each of the values must be one of the choices.
+func OneOf(choices ...string) ParamOption {
+	return func(opts *paramOptions) {
+		opts.choices = choices
+	}
+}
+
+// IgnoreCase makes OneOf compare values case-insensitively.  Value is then replaced with the choice it matches, so
+// that handler gets it as defined, eg. 'JSON' becomes 'json'.
+func IgnoreCase() ParamOption {
+	return func(opts *paramOptions) {
+		opts.ignoreCase = true
+	}
+}
+
+// Min requires value of TypeInt or TypeFloat param to be greater than or equal to minValue.
+func Min(minValue float64) ParamOption {
+	return func(opts *paramOptions) {
+		opts.minValue = &minValue
+	}
+}
+
+// Max requires value of TypeInt or TypeFloat param to be less than or equal to maxValue.
+func Max(maxValue float64) ParamOption {
+	return func(opts *paramOptions) {
+		opts.maxValue = &maxValue
+	}
+}
+
+// BitSize requires value of TypeInt param to fit in an integer of the size, eg. 8 for int8.  Default is 64.
+func BitSize(bits int) ParamOption {
+	return func(opts *paramOptions) {
+		opts.bitSize = bits
+	}
+}
+
+// WithType sets ValueType of a param of TypeCustom.  Values are parsed and validated with it, and its placeholder and
+// completion are used when param does not have its own.
+func WithType(valueType ValueType) ParamOption {
+	return func(opts *paramOptions) {
+		opts.valueType = valueType
+	}
+}
+
+// WithValidator adds functions that check each value of param after the type validation, eg. whether a string is
+// a valid semantic version.  Option can be used many times and validators are called in the order they were added.
+func WithValidator(validators ...Validator) ParamOption {
+	return func(opts *paramOptions) {
+		opts.validators = append(opts.validators, validators...)
+	}
+}
+
+// HideInput makes the value not shown while it is typed when prompting for it, eg. for passwords.  See WithPrompt.
+func HideInput() ParamOption {
+	return func(opts *paramOptions) {
+		opts.hideInput = true
+	}
+}
+
+// Sensitive marks value of param as secret, eg. a token.  Value is masked in errors and help screen, and it is not
+// shown when prompting for it.  Sensitive flag gets additional '--NAME-file' flag that takes path to the file with
+// the value, or '-' to read it from the input, so that the value does not have to be passed in the command line.
+// See WithClearSensitive for removing the values once the handler is done.
+func Sensitive() ParamOption {
+	return func(opts *paramOptions) {
+		opts.sensitive = true
+	}
+}
+
+// DeprecatedFlag marks flag as deprecated.  Flag still works but a warning with the message, eg. "use --output
+// instead", is printed when it is passed.  See Broccli.Warnings.
+func DeprecatedFlag(message string) ParamOption {
+	return func(opts *paramOptions) {
+		opts.deprecated = true
+		opts.deprecation = message
+	}
+}
@@ -1,7 +1,9 @@
 package broccli
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -35,8 +37,8 @@ func TestParamValidationBasic(t *testing.T) {
 		t.Errorf("Float param should validate")
 	}
 
-	if p.validateValue("48") == nil {
-		t.Errorf("Float param should not validate int")
+	if p.validateValue("48") != nil {
+		t.Errorf("Float param should validate int")
 	}
 
 	if p.validateValue("aa") == nil {
@@ -241,3 +243,164 @@ func TestParamValidationFiles(t *testing.T) {
 		t.Errorf("PathFile param with IsExistent should fail")
 	}
 }
+
+// TestParamDefault tests validation of default values and rendering them in help line.
+func TestParamDefault(t *testing.T) {
+	t.Parallel()
+
+	p := &param{
+		name:      "level",
+		valueType: TypeInt,
+		options:   paramOptions{defaultValue: "5"},
+	}
+	p.mustValidateDefault()
+
+	if !strings.Contains(p.helpLine(), "(default: 5)") {
+		t.Errorf("Help line should contain default value")
+	}
+
+	if p.valueOrDefault("") != "5" || p.valueOrDefault("7") != "7" {
+		t.Errorf("Default value should be used only when value is empty")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Invalid default value should panic")
+		}
+	}()
+
+	p.options.defaultValue = "abc"
+	p.mustValidateDefault()
+}
+
+// TestParamChoices tests values that must be one of the choices.
+func TestParamChoices(t *testing.T) {
+	t.Parallel()
+
+	p := &param{
+		name:      "output",
+		valueType: TypeAlphanumeric,
+		flags:     AllowMultipleValues,
+		options:   paramOptions{choices: []string{"json", "yaml", "table"}},
+	}
+
+	if p.validateValue("json") != nil || p.validateValue("yaml,table") != nil || p.validateValue("") != nil {
+		t.Errorf("Valid choices should validate")
+	}
+
+	if !errors.Is(p.validateValue("xml"), ErrParamValueInvalid) || p.validateValue("json,JSON") == nil {
+		t.Errorf("Invalid choices should not validate")
+	}
+
+	p.options.ignoreCase = true
+	if p.validateValue("JSON,Yaml") != nil || p.canonicalValue("JSON,Yaml") != "json,yaml" {
+		t.Errorf("Choices should be compared case-insensitively")
+	}
+
+	if !strings.Contains(p.helpLine(), "(one of: json, yaml, table)") {
+		t.Errorf("Help line should contain choices")
+	}
+
+	p = &param{name: "num", valueType: TypeInt, options: paramOptions{choices: []string{"1", "x"}}}
+	if p.validateValue("1") != nil || p.validateValue("x") == nil {
+		t.Errorf("Choices should be checked in addition to the type")
+	}
+
+	p = &param{
+		name:      "file",
+		valueType: TypePathFile,
+		flags:     IsExistent,
+		options:   paramOptions{choices: []string{"/nonexistent"}},
+	}
+	if p.validateValue("/nonexistent") == nil {
+		t.Errorf("Choices should be checked in addition to file existence")
+	}
+}
+
+// TestParamNumbers tests signed numbers, bit size and bounds of numeric params.
+func TestParamNumbers(t *testing.T) {
+	t.Parallel()
+
+	p := &param{name: "level", valueType: TypeInt, flags: AllowMultipleValues, options: paramOptions{bitSize: 8}}
+
+	if p.validateValue("-128,+5,127") != nil {
+		t.Errorf("Signed ints within bit size should validate")
+	}
+
+	err := p.validateValue("128")
+	if err == nil || err.Error() != "param value invalid: 128 is out of range of 8-bit integer" {
+		t.Errorf("Int out of bit size range should not validate: %v", err)
+	}
+
+	if p.validateValue("1.5") == nil || p.validateValue("1,,2") == nil {
+		t.Errorf("Invalid ints should not validate")
+	}
+
+	Min(1)(&p.options)
+	Max(10)(&p.options)
+
+	err = p.validateValue("0")
+	if err == nil || err.Error() != "param value invalid: must be at least 1" {
+		t.Errorf("Int below minimum should not validate: %v", err)
+	}
+
+	err = p.validateValue("5,11")
+	if err == nil || err.Error() != "param value invalid: must be at most 10" {
+		t.Errorf("Int above maximum should not validate: %v", err)
+	}
+
+	if !strings.Contains(p.helpLine(), "(min: 1) (max: 10)") {
+		t.Errorf("Help line should contain bounds")
+	}
+
+	p = &param{name: "ratio", valueType: TypeFloat, options: paramOptions{}}
+	Min(-0.5)(&p.options)
+
+	if p.validateValue("-0.25") != nil || p.validateValue("1e3") != nil || p.validateValue("7") != nil {
+		t.Errorf("Valid floats should validate")
+	}
+
+	if p.validateValue("NaN") == nil || p.validateValue("Inf") == nil || p.validateValue("1e400") == nil {
+		t.Errorf("Non-finite floats should not validate")
+	}
+
+	err = p.validateValue("-1")
+	if err == nil || err.Error() != "param value invalid: must be at least -0.5" {
+		t.Errorf("Float below minimum should not validate: %v", err)
+	}
+}
+
+// TestParamInvalidBounds tests that Min, Max and BitSize set on a wrong param panic.
+func TestParamInvalidBounds(t *testing.T) {
+	t.Parallel()
+
+	minValue := 10.0
+	maxValue := 1.0
+
+	tests := map[string]*param{
+		"min on string":     {name: "name", valueType: TypeString, options: paramOptions{minValue: &minValue}},
+		"max on bool":       {name: "force", valueType: TypeBool, options: paramOptions{maxValue: &maxValue}},
+		"bit size on float": {name: "ratio", valueType: TypeFloat, options: paramOptions{bitSize: 32}},
+		"invalid bit size":  {name: "level", valueType: TypeInt, options: paramOptions{bitSize: 12}},
+		"min above max": {
+			name: "level", valueType: TypeInt, options: paramOptions{minValue: &minValue, maxValue: &maxValue},
+		},
+	}
+
+	for name, p := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Param should panic")
+				}
+			}()
+
+			p.mustBeValid()
+		})
+	}
+
+	p := &param{name: "level", valueType: TypeInt, options: paramOptions{minValue: &maxValue, bitSize: 8}}
+	p.mustBeValid()
+}
@@ -0,0 +1,133 @@
+package broccli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// isTerminal returns true when r is a terminal, eg. stdin of the program run by a human.
+func isTerminal(r io.Reader) bool {
+	file, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// canPrompt returns true when values of missing required params should be asked for, ie. prompting was enabled with
+// WithPrompt and the input is a terminal.
+func (c *Broccli) canPrompt() bool {
+	return c.options.prompt && c.options.isTerminal(c.options.stdin)
+}
+
+// promptValue asks for value of param until a valid one is entered.  Label is the name of param shown in the
+// question, eg. '--name' or 'NAME'.  Empty string is returned when the input ends.
+func (c *Broccli) promptValue(p *param, label string) string {
+	for {
+		value, err := c.promptAnswer(p, label)
+		if err != nil {
+			return ""
+		}
+
+		err = p.validateValue(value)
+		if err == nil && value != "" {
+			return value
+		}
+
+		if err == nil {
+			err = ErrParamValueMissing
+		}
+
+		_, _ = fmt.Fprintf(c.options.stderr, "ERROR: %s\n", err.Error())
+	}
+}
+
+// promptAnswer asks for value of param once and returns the answer.  Boolean params are answered with yes or no, and
+// choices can be picked by their number.
+func (c *Broccli) promptAnswer(p *param, label string) (string, error) {
+	question := p.usage
+	if question == "" {
+		question = p.name
+	}
+
+	question += " (" + label + ")"
+
+	switch {
+	case p.valueType == TypeBool:
+		question += " [y/n]"
+	case len(p.options.choices) > 0:
+		_, _ = fmt.Fprintf(c.options.stderr, "%s:\n", question)
+		for i, choice := range p.options.choices {
+			_, _ = fmt.Fprintf(c.options.stderr, "  %d) %s\n", i+1, choice)
+		}
+
+		question = fmt.Sprintf("Choose 1-%d", len(p.options.choices))
+	}
+
+	_, _ = fmt.Fprintf(c.options.stderr, "%s: ", question)
+
+	answer, err := c.readAnswer(p.options.hideInput || p.options.sensitive)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case p.valueType == TypeBool:
+		return promptBool(answer), nil
+	case len(p.options.choices) > 0:
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(p.options.choices) {
+			return p.options.choices[i-1], nil
+		}
+	}
+
+	return answer, nil
+}
+
+// readAnswer reads a line from the input.  When hidden is true and the input is a terminal, typed chars are not
+// shown.
+func (c *Broccli) readAnswer(hidden bool) (string, error) {
+	if c.input == nil {
+		c.input = bufio.NewReader(c.options.stdin)
+	}
+
+	if file, ok := c.options.stdin.(*os.File); ok && hidden && isTerminal(file) {
+		restoreEcho, err := disableEcho(file)
+		if err == nil {
+			defer func() {
+				restoreEcho()
+				_, _ = fmt.Fprintln(c.options.stderr)
+			}()
+		}
+	}
+
+	answer, err := c.input.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || answer == "") {
+		return "", fmt.Errorf("error reading answer: %w", err)
+	}
+
+	return strings.TrimSpace(answer), nil
+}
+
+// promptBool converts yes or no answer to a boolean value.  Other answers are returned as they are so that they are
+// validated as any other value.
+func promptBool(answer string) string {
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return "true"
+	case "n", "no":
+		return "false"
+	default:
+		return answer
+	}
+}
@@ -0,0 +1,90 @@
+package broccli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// newPromptTestCLI returns CLI with prompting enabled that reads answers from input as if it was a terminal.
+func newPromptTestCLI(input string, stderr io.Writer, terminal bool) *Broccli {
+	cli := newTestCLI(WithPrompt(), WithInput(strings.NewReader(input)), WithErrorOutput(stderr))
+	cli.options.isTerminal = func(_ io.Reader) bool { return terminal }
+
+	cmd := cli.Command("login", "Logs in", func(_ context.Context, _ *Broccli) int { return 0 })
+	cmd.Flag("user", "u", "USER", "User name", TypeAlphanumeric, IsRequired)
+	cmd.Flag("password", "p", "PASSWORD", "Password", TypeString, IsRequired, HideInput())
+	cmd.Flag("region", "", "REGION", "Region", TypeString, IsRequired, OneOf("eu", "us"))
+	cmd.Flag("remember", "", "", "Remember me", TypeBool, IsRequired)
+	cmd.Flag("port", "", "PORT", "Port", TypeInt, 0)
+	cmd.Arg("server", "SERVER", "Server", TypeString, IsRequired)
+
+	return cli
+}
+
+// TestPrompt tests asking for values of missing required params.
+func TestPrompt(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+
+	// flags are asked for in alphabetical order, then args
+	cli := newPromptTestCLI("secret\n3\n2\nmaybe\nyes\njohn.doe\njohn\nhost\n", &stderr, true)
+
+	_, err := cli.Parse([]string{"test", "login"})
+	if err != nil {
+		t.Fatalf("Parse() returned error: %s", err.Error())
+	}
+
+	if cli.Flag("password") != "secret" || cli.Flag("region") != "us" || cli.Flag("remember") != "true" ||
+		cli.Flag("user") != "john" || cli.Flag("port") != "" || cli.Arg("server") != "host" {
+		t.Errorf("Prompted values are invalid: %v %v", cli.parsedFlags, cli.parsedArgs)
+	}
+
+	for _, expected := range []string{
+		"Password (--password): ",
+		"Region (--region):\n  1) eu\n  2) us\nChoose 1-2: ",
+		"Remember me (--remember) [y/n]: ",
+		"User name (--user): ",
+		"Server (SERVER): ",
+		"ERROR: param value invalid: must be one of eu, us\n",
+		"ERROR: param value invalid\n",
+	} {
+		if !strings.Contains(stderr.String(), expected) {
+			t.Errorf("Prompt output should contain %q:\n%s", expected, stderr.String())
+		}
+	}
+
+	if strings.Contains(stderr.String(), "Port") {
+		t.Errorf("Optional params should not be asked for")
+	}
+}
+
+// TestPromptNotTerminal tests that missing values are not asked for when input is not a terminal or ends.
+func TestPromptNotTerminal(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+
+	cli := newPromptTestCLI("secret\n", &stderr, false)
+
+	_, err := cli.Parse([]string{"test", "login"})
+	if !errors.Is(err, ErrParamValueMissing) || stderr.Len() > 0 {
+		t.Errorf("Parse() should not prompt when input is not a terminal: %v", err)
+	}
+
+	cli = newPromptTestCLI("secret\n", &stderr, true)
+
+	_, err = cli.Parse([]string{"test", "login", "-u", "john", "--region", "eu", "--remember", "host"})
+	if err != nil || cli.Flag("password") != "secret" {
+		t.Errorf("Parse() should prompt only for missing values: %v", err)
+	}
+
+	_, err = cli.Parse([]string{"test", "login"})
+	if !errors.Is(err, ErrParamValueMissing) {
+		t.Errorf("Parse() should return error when input ends: %v", err)
+	}
+}
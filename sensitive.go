@@ -0,0 +1,159 @@
+package broccli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// sensitiveMask is shown instead of values of params with Sensitive option.
+const sensitiveMask = "******"
+
+// maskedError hides value of sensitive param in the message of the wrapped error.
+type maskedError struct {
+	err    error
+	values []string
+}
+
+func (e *maskedError) Error() string {
+	message := e.err.Error()
+	for _, value := range e.values {
+		message = strings.ReplaceAll(message, value, sensitiveMask)
+	}
+
+	return message
+}
+
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
+
+// maskError returns err with value hidden in its message when param is sensitive.
+func (p *param) maskError(err error, value string) error {
+	if !p.options.sensitive || err == nil || value == "" {
+		return err
+	}
+
+	values := append([]string{value}, p.stringValues(value)...)
+	// longer values first so that parts of them are not left in the message
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	nonEmpty := []string{}
+	for _, v := range values {
+		if v != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+
+	return &maskedError{err: err, values: nonEmpty}
+}
+
+// displayValue returns value that can be shown to the user, ie. masked value when param is sensitive.
+func (p *param) displayValue(value string) string {
+	if p.options.sensitive && value != "" {
+		return sensitiveMask
+	}
+
+	return value
+}
+
+// addFileFlag adds a flag that takes path to the file with value of sensitive flag, eg. '--token-file' for
+// '--token'.  Path '-' means that the value is read from the input.
+func (c *Command) addFileFlag(flag *param) {
+	if !flag.options.sensitive || flag.flags&AllowRepeated > 0 {
+		return
+	}
+
+	name := flag.name + "-file"
+	if _, exists := c.flags[name]; exists {
+		panic(fmt.Sprintf("flag %s cannot be added for sensitive flag %s as it already exists", name, flag.name))
+	}
+
+	flag.fileFlag = &param{
+		name:             name,
+		valuePlaceholder: "PATH",
+		usage:            fmt.Sprintf("File to read --%s from, or - for input", flag.name),
+		valueType:        TypeString,
+		options:          paramOptions{},
+	}
+	c.flags[name] = flag.fileFlag
+}
+
+// readFileFlag returns value of sensitive flag from the file passed in its file flag.  Trailing newline is removed.
+func (c *Broccli) readFileFlag(flag *param, line *commandLine) (string, error) {
+	path := line.value(flag.fileFlag)
+	if path == "" {
+		return "", nil
+	}
+
+	var (
+		dat []byte
+		err error
+	)
+
+	if path == "-" {
+		input := c.options.stdin
+		if c.input != nil {
+			input = c.input
+		}
+
+		dat, err = io.ReadAll(input)
+	} else {
+		dat, err = os.ReadFile(filepath.Clean(path))
+	}
+
+	if err != nil {
+		err = errFileOpenInPath("read", path)
+
+		return "", &ParamError{Kind: ParamFlag, Name: flag.fileFlag.name, Value: path, Err: err}
+	}
+
+	return strings.TrimRight(string(dat), "\r\n"), nil
+}
+
+// clearSensitive removes values of sensitive params of the command once its handler is done, when it was requested
+// with WithClearSensitive.  Struct fields bound to these params are set to zero values.
+func (c *Broccli) clearSensitive(cmd *Command) {
+	if !c.options.clearSensitive || cmd == nil {
+		return
+	}
+
+	for name, flag := range cmd.allFlags() {
+		if flag.options.sensitive {
+			delete(c.parsedFlags, name)
+			delete(c.parsedRepeated, name)
+		}
+	}
+
+	for name, arg := range cmd.args {
+		if arg.options.sensitive {
+			delete(c.parsedArgs, name)
+			delete(c.parsedVariadic, name)
+		}
+	}
+
+	for _, env := range []map[string]*param{c.env, cmd.env} {
+		for name, envVar := range env {
+			if envVar.options.sensitive {
+				delete(c.parsedEnv, name)
+			}
+		}
+	}
+
+	for bound := cmd; bound != nil; bound = bound.parent {
+		for _, b := range bound.bindings {
+			p := bound.flags[b.name]
+			if b.kind == ParamArg {
+				p = bound.args[b.name]
+			}
+
+			if p.options.sensitive {
+				b.field.Set(reflect.Zero(b.field.Type()))
+			}
+		}
+	}
+}
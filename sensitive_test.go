@@ -0,0 +1,146 @@
+package broccli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSensitiveMasking tests that values of sensitive params are not shown in errors and help.
+func TestSensitiveMasking(t *testing.T) {
+	t.Parallel()
+
+	cli := newTestCLI(WithLookupEnv(func(key string) (string, bool) {
+		if key == "API_KEY" {
+			return "key-123", true
+		}
+
+		return "", false
+	}))
+	cmd := cli.Command("call", "Calls the API", func(_ context.Context, _ *Broccli) int { return 0 })
+	cmd.Flag("token", "t", "TOKEN", "API token", TypeAlphanumeric, IsRequired, Sensitive())
+	cmd.Flag("pin", "", "PIN", "PIN", TypeInt, 0, Sensitive(), Max(9999), WithDefault("1234"))
+	cmd.Env("API_KEY", "API key", TypeInt, 0, Sensitive())
+
+	_, err := cli.Parse([]string{"test", "call", "-t", "abc"})
+
+	var paramErr *ParamError
+	if !errors.As(err, &paramErr) || paramErr.Value != sensitiveMask || strings.Contains(err.Error(), "key-123") {
+		t.Errorf("Env var value should be masked: %v", err)
+	}
+
+	cmd.env = map[string]*param{}
+
+	_, err = cli.Parse([]string{"test", "call", "-t", "abc", "--pin", "123456789012345678901234567890"})
+	if !errors.Is(err, ErrParamValueInvalid) || strings.Contains(err.Error(), "1234567890") {
+		t.Errorf("Flag value should be masked: %v", err)
+	}
+
+	if err.Error() != "Flag pin: param value invalid: "+sensitiveMask+" is out of range of 64-bit integer" {
+		t.Errorf("Error message is invalid: %s", err.Error())
+	}
+
+	help := cmd.helpMessage("test")
+	if !strings.Contains(help, "(default: "+sensitiveMask+")") || strings.Contains(help, "1234") {
+		t.Errorf("Default value should be masked in help:\n%s", help)
+	}
+
+	if !strings.Contains(help, "--token-file PATH") {
+		t.Errorf("Help should contain file flag:\n%s", help)
+	}
+}
+
+// TestSensitiveFile tests reading value of sensitive flag from a file or the input.
+func TestSensitiveFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	err := os.WriteFile(path, []byte("fromfile\n"), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile() returned error: %s", err.Error())
+	}
+
+	cli := newTestCLI(WithInput(strings.NewReader("frominput\r\n")))
+	cmd := cli.Command("call", "Calls the API", func(_ context.Context, _ *Broccli) int { return 0 })
+	cmd.Flag("token", "t", "TOKEN", "API token", TypeAlphanumeric, IsRequired, Sensitive())
+
+	testCases := map[string][]string{
+		"fromfile":  {"test", "call", "--token-file", path},
+		"frominput": {"test", "call", "--token-file", "-"},
+		"fromline":  {"test", "call", "--token-file", path, "--token", "fromline"},
+	}
+
+	for expected, args := range testCases {
+		_, err = cli.Parse(args)
+		if err != nil || cli.Flag("token") != expected {
+			t.Errorf("Parse(%v) should have set token to %s instead of %s: %v", args, expected, cli.Flag("token"), err)
+		}
+	}
+
+	_, err = cli.Parse([]string{"test", "call", "--token-file", path + ".missing"})
+	if !errors.Is(err, ErrFileOpen) || !strings.HasPrefix(err.Error(), "Flag token-file: ") {
+		t.Errorf("Parse() should have returned file error instead of %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Flag() should have panicked when file flag already exists")
+		}
+	}()
+
+	cmd.Flag("key-file", "", "PATH", "Key file", TypeString, 0)
+	cmd.Flag("key", "", "KEY", "Key", TypeString, 0, Sensitive())
+}
+
+// TestClearSensitive tests removing values of sensitive params after the handler.
+func TestClearSensitive(t *testing.T) {
+	t.Parallel()
+
+	var opts struct {
+		Token string `flag:"token" sensitive:"true"`
+		User  string `flag:"user"`
+	}
+
+	token := ""
+	cli := newTestCLI(WithClearSensitive())
+	cmd := cli.Command("call", "Calls the API", func(_ context.Context, c *Broccli) int {
+		token = c.Flag("token")
+
+		return 0
+	})
+	cmd.Bind(&opts)
+	cmd.Arg("secret", "SECRET", "Secret", TypeString, 0, Sensitive())
+
+	exitCode, err := cli.Execute(context.Background(), []string{"test", "call", "--token", "abc", "--user", "joe", "s3"})
+	if err != nil || exitCode != 0 || token != "abc" {
+		t.Fatalf("Execute() returned %d and %v, handler got token %s", exitCode, err, token)
+	}
+
+	if cli.Flag("token") != "" || cli.Arg("secret") != "" || opts.Token != "" {
+		t.Errorf("Sensitive values should have been removed")
+	}
+
+	if cli.Flag("user") != "joe" || opts.User != "joe" {
+		t.Errorf("Other values should have been kept")
+	}
+}
+
+// TestSensitiveVariadicArg tests that values of sensitive variadic arg are masked in errors.
+func TestSensitiveVariadicArg(t *testing.T) {
+	t.Parallel()
+
+	cli := newTestCLI()
+	cmd := cli.Command("unlock", "Unlocks the vault", func(_ context.Context, _ *Broccli) int { return 0 })
+	cmd.Arg("keys", "KEYS", "Unseal keys", TypeInt, IsRequired, Variadic(1, 0), Sensitive())
+
+	_, err := cli.Parse([]string{"test", "unlock", "1", "secret"})
+
+	var paramErr *ParamError
+	if !errors.As(err, &paramErr) || paramErr.Value != sensitiveMask || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Variadic arg value should be masked: %v", err)
+	}
+}
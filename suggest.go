@@ -0,0 +1,79 @@
+package broccli
+
+import "strings"
+
+// suggestion returns a note with the candidate that is the closest to value, eg. " (did you mean start?)", or empty
+// string when none of the candidates is close enough.  Prefix is added to the suggested candidate, eg. '--'.
+func suggestion(value string, candidates []string, prefix string) string {
+	suggested := closestCandidate(value, candidates)
+	if suggested == "" {
+		return ""
+	}
+
+	return " (did you mean " + prefix + suggested + "?)"
+}
+
+// closestCandidate returns candidate with the lowest edit distance to value.  Candidates that differ in more than one
+// third of the chars are skipped, so that unrelated names are not suggested.
+func closestCandidate(value string, candidates []string) string {
+	best := ""
+	bestDistance := max(1, len([]rune(value))/3) + 1
+
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+		if distance < bestDistance && distance < len([]rune(value)) {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the number of chars that have to be inserted, removed or replaced, or adjacent chars that have
+// to be swapped, to turn a into b (optimal string alignment distance).  Swapped chars are a common typo, eg. 'strat'.
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	// rows of the distance matrix: two rows back is needed for swapped chars
+	beforePrevious := make([]int, len(target)+1)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+
+			if i > 1 && j > 1 && source[i-1] == target[j-2] && source[i-2] == target[j-1] {
+				current[j] = min(current[j], beforePrevious[j-2]+1)
+			}
+		}
+
+		beforePrevious, previous, current = previous, current, beforePrevious
+	}
+
+	return previous[len(target)]
+}
+
+// commandCandidates returns names and aliases of commands that are not hidden.
+func commandCandidates(commands map[string]*Command) []string {
+	candidates := []string{}
+
+	for _, name := range sortedVisibleCommands(commands) {
+		candidates = append(candidates, name)
+		candidates = append(candidates, commands[name].options.aliases...)
+	}
+
+	return candidates
+}
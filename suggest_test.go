@@ -0,0 +1,93 @@
+package broccli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestEditDistance tests counting edits needed to turn one string into another.
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"start", "start", 0},
+		{"strat", "start", 1},
+		{"ab", "ba", 1},
+		{"verbos", "verbose", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"zażółć", "zazolc", 4},
+	}
+
+	for _, testCase := range testCases {
+		if got := editDistance(testCase.a, testCase.b); got != testCase.distance {
+			t.Errorf("editDistance(%q, %q) should be %d instead of %d", testCase.a, testCase.b, testCase.distance, got)
+		}
+	}
+
+	if suggestion("strat", []string{"stop", "start"}, "") != " (did you mean start?)" ||
+		suggestion("x", []string{"v"}, "-") != "" || suggestion("deploy", []string{"start"}, "") != "" {
+		t.Errorf("suggestion() returned invalid suggestions")
+	}
+}
+
+// TestSuggestions tests suggestions in errors for mistyped commands, flags and choices.
+func TestSuggestions(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+
+	cli := newTestCLI(WithErrorOutput(&stderr))
+	handler := func(_ context.Context, _ *Broccli) int { return 0 }
+
+	start := cli.Command("start", "Starts the game", handler)
+	start.Flag("verbose", "v", "", "Verbose output", TypeBool, 0)
+	start.Flag("output", "o", "FORMAT", "Output format", TypeString, 0, OneOf("json", "yaml"))
+	cli.Command("remove", "Removes a file", handler, WithAliases("rm"))
+	cli.Command("secret", "Hidden command", handler, Hidden())
+
+	testCases := []struct {
+		args    []string
+		kind    error
+		message string
+	}{
+		{[]string{"test", "strat"}, ErrCommandInvalid, "invalid command: strat (did you mean start?)"},
+		{[]string{"test", "rn"}, ErrCommandInvalid, "invalid command: rn (did you mean rm?)"},
+		{[]string{"test", "secrt"}, ErrCommandInvalid, "invalid command: secrt"},
+		{[]string{"test", "start", "--verbos"}, ErrFlagUnknown, "Flag --verbos: unknown flag (did you mean --verbose?)"},
+		{[]string{"test", "start", "--outptu=json"}, ErrFlagUnknown, "Flag --outptu: unknown flag (did you mean --output?)"},
+		{[]string{"test", "start", "-vx"}, ErrFlagUnknown, "Flag -x: unknown flag"},
+		{[]string{"test", "start", "--deploy"}, ErrFlagUnknown, "Flag --deploy: unknown flag"},
+		{
+			[]string{"test", "start", "-o", "jsno"},
+			ErrParamValueInvalid,
+			"Flag output: param value invalid: must be one of json, yaml (did you mean json?)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err := cli.Parse(testCase.args)
+		if !errors.Is(err, testCase.kind) || err.Error() != testCase.message {
+			t.Errorf("Parse() with %v should have returned %q instead of %v", testCase.args, testCase.message, err)
+		}
+	}
+
+	var paramErr *ParamError
+
+	_, err := cli.Parse([]string{"test", "start", "--verbos"})
+	if !errors.As(err, &paramErr) || paramErr.Kind != ParamFlag || paramErr.Name != "verbos" {
+		t.Errorf("Unknown flag should be returned as ParamError: %v", err)
+	}
+
+	exitCode := cli.RunArgs(context.Background(), []string{"test", "strat"})
+	if exitCode != 1 || stderr.String() != "ERROR: invalid command: strat (did you mean start?)\n"+
+		"Run 'test --help' for usage.\n" {
+		t.Errorf("RunArgs() should have printed error with a hint:\n%s", stderr.String())
+	}
+}
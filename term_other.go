@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package broccli
+
+import (
+	"errors"
+	"os"
+)
+
+var errEchoUnsupported = errors.New("hiding input is not supported on this platform")
+
+// disableEcho returns an error as hiding typed chars is not supported, so hidden input is shown.
+func disableEcho(_ *os.File) (func(), error) {
+	return nil, errEchoUnsupported
+}
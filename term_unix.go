@@ -0,0 +1,36 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package broccli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// disableEcho turns off showing typed chars in the terminal and returns a function that turns it back on.
+func disableEcho(file *os.File) (func(), error) {
+	fd := file.Fd()
+
+	var termios syscall.Termios
+
+	//nolint:gosec
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&termios)))
+	if errno != 0 {
+		return nil, errno
+	}
+
+	hidden := termios
+	hidden.Lflag &^= syscall.ECHO
+
+	//nolint:gosec
+	_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&hidden)))
+	if errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		//nolint:gosec
+		_, _, _ = syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&termios)))
+	}, nil
+}
@@ -0,0 +1,249 @@
+package broccli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// errFlagUnknown returns error for flag that was passed as arg, eg. '--verbos', with the name of the closest flag.
+func errFlagUnknown(arg string, flags map[string]*param) error {
+	typed, _, _ := strings.Cut(arg, "=")
+	name := strings.TrimLeft(typed, "-")
+	isLong := strings.HasPrefix(typed, "--")
+
+	candidates := []string{}
+	prefix := "--"
+
+	for flagName, flag := range flags {
+		if isLong || len(name) > 1 {
+			candidates = append(candidates, flagName)
+		} else if flag.alias != "" {
+			candidates = append(candidates, flag.alias)
+			prefix = "-"
+		}
+	}
+
+	sort.Strings(candidates)
+
+	return &ParamError{
+		Kind:  ParamFlag,
+		Name:  name,
+		Err:   fmt.Errorf("%w%s", ErrFlagUnknown, suggestion(name, candidates, prefix)),
+		label: typed,
+	}
+}
+
+// negativeNumber matches args such as -5, -1.5 or -2e3 that are not flags.
+var negativeNumber = regexp.MustCompile(`^-[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// commandLine contains flags and args taken from the command line.
+type commandLine struct {
+	// flagValues contains values of each occurrence of flags, by flag name
+	flagValues map[string][]string
+	// passedNames and passedAliases contain names of flags that were passed with --name or -alias
+	passedNames   map[string]bool
+	passedAliases map[string]bool
+	// failedNames contains names of flags that were passed without a value
+	failedNames map[string]bool
+	args        []string
+}
+
+// value returns value of the last occurrence of flag, or empty string when flag was not passed.
+func (l *commandLine) value(flag *param) string {
+	values := l.flagValues[flag.name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+// tokenize splits args into flags and positional args the GNU way:
+//   - long flags are passed as '--name value' or '--name=value',
+//   - short flags are passed as '-a value', '-a=value' or '-avalue', and boolean ones can be bundled, eg. '-abc',
+//   - boolean flags can be negated with '--no-name' or set with '--name=false',
+//   - flags and positional args can be mixed, and everything after '--' is a positional arg.
+//
+// Aliases longer than one char and names passed with a single hyphen are accepted as well.  Unknown flags and flags
+// with missing values are left out, and when all errors are reported, tokenizing goes on and they are returned once it
+// is done.  It is not known if an unknown flag takes a value, so the arg following it is treated as a positional arg.
+func (c *Broccli) tokenize(flags map[string]*param, args []string) (*commandLine, error) {
+	line := newCommandLine()
+	errs := []error{}
+	aliases := flagAliases(flags)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			line.args = append(line.args, args[i+1:]...)
+
+			break
+		}
+
+		if !isFlag(arg, aliases) {
+			line.args = append(line.args, arg)
+
+			continue
+		}
+
+		var err error
+
+		i, err = line.addFlag(flags, aliases, args, i)
+		if err != nil && c.stopOnError(&errs, err) {
+			return line, err
+		}
+	}
+
+	return line, errors.Join(errs...)
+}
+
+func newCommandLine() *commandLine {
+	return &commandLine{
+		flagValues:    map[string][]string{},
+		passedNames:   map[string]bool{},
+		passedAliases: map[string]bool{},
+		failedNames:   map[string]bool{},
+		args:          []string{},
+	}
+}
+
+// flagAliases returns flags by their aliases.
+func flagAliases(flags map[string]*param) map[string]*param {
+	aliases := map[string]*param{}
+
+	for _, flag := range flags {
+		if flag.alias != "" {
+			aliases[flag.alias] = flag
+		}
+	}
+
+	return aliases
+}
+
+// isFlag returns true when arg is a flag and not a positional arg such as '-', '--' or a negative number.
+func isFlag(arg string, aliases map[string]*param) bool {
+	if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--" {
+		return false
+	}
+
+	_, isAlias := aliases[strings.TrimPrefix(arg, "-")]
+
+	return isAlias || !negativeNumber.MatchString(arg)
+}
+
+// addFlag adds flag that is at position i in args, which can be a negated boolean flag or bundled short flags as
+// well.  It returns position of the last arg that was used.
+func (l *commandLine) addFlag(flags map[string]*param, aliases map[string]*param, args []string, i int) (int, error) {
+	arg := args[i]
+	name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	isLong := strings.HasPrefix(arg, "--")
+
+	flag, isAlias := lookupFlag(flags, aliases, name, isLong)
+
+	// negated boolean flag
+	if flag == nil && isLong && strings.HasPrefix(name, "no-") {
+		if negated, ok := flags[strings.TrimPrefix(name, "no-")]; ok && negated.valueType == TypeBool && !hasValue {
+			l.add(negated, false, "false")
+
+			return i, nil
+		}
+	}
+
+	// bundled short flags
+	if flag == nil && !isLong && !hasValue {
+		return l.addBundle(aliases, args, i)
+	}
+
+	if flag == nil {
+		return i, errFlagUnknown(arg, flags)
+	}
+
+	if flag.valueType == TypeBool && !hasValue {
+		value = "true"
+	} else if !hasValue {
+		if i+1 >= len(args) {
+			return i, l.valueMissing(flag)
+		}
+
+		i++
+		value = args[i]
+	}
+
+	l.add(flag, isAlias, value)
+
+	return i, nil
+}
+
+// addBundle adds flags from bundled short flags, eg. '-abc' or '-ovalue', that are at position i in args.  It returns
+// position of the last arg that was used.
+func (l *commandLine) addBundle(aliases map[string]*param, args []string, i int) (int, error) {
+	bundle := strings.TrimPrefix(args[i], "-")
+
+	for j, char := range bundle {
+		flag, ok := aliases[string(char)]
+		if !ok {
+			return i, errFlagUnknown("-"+string(char), aliases)
+		}
+
+		if flag.valueType == TypeBool {
+			l.add(flag, true, "true")
+
+			continue
+		}
+
+		// rest of the bundle is the value, or the next arg when there is nothing left
+		value := bundle[j+1:]
+		if value == "" {
+			if i+1 >= len(args) {
+				return i, l.valueMissing(flag)
+			}
+
+			i++
+			value = args[i]
+		}
+
+		l.add(flag, true, value)
+
+		break
+	}
+
+	return i, nil
+}
+
+// valueMissing returns error for flag that was passed without a value, and marks the flag as failed so that it is not
+// reported again when flags are validated.
+func (l *commandLine) valueMissing(flag *param) error {
+	l.failedNames[flag.name] = true
+
+	return &ParamError{Kind: ParamFlag, Name: flag.name, Err: ErrParamValueMissing}
+}
+
+func (l *commandLine) add(flag *param, isAlias bool, value string) {
+	l.flagValues[flag.name] = append(l.flagValues[flag.name], value)
+
+	if isAlias {
+		l.passedAliases[flag.name] = true
+	} else {
+		l.passedNames[flag.name] = true
+	}
+}
+
+// lookupFlag returns flag with the name or alias.  Long flags are looked up by name first and short ones by alias.
+// Second returned value is true when flag was found by alias.
+func lookupFlag(flags map[string]*param, aliases map[string]*param, name string, isLong bool) (*param, bool) {
+	flag, byName := flags[name]
+	alias, byAlias := aliases[name]
+
+	switch {
+	case byName && (isLong || !byAlias):
+		return flag, false
+	case byAlias:
+		return alias, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,101 @@
+package broccli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestTokenize tests splitting command line into flags and args.
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	flags := map[string]*param{
+		"verbose": {name: "verbose", alias: "v", valueType: TypeBool},
+		"quiet":   {name: "quiet", alias: "q", valueType: TypeBool},
+		"color":   {name: "color", valueType: TypeBool},
+		"output":  {name: "output", alias: "o", valueType: TypeString},
+		"level":   {name: "level", alias: "lv", valueType: TypeInt},
+	}
+
+	testCases := []struct {
+		args   []string
+		values map[string][]string
+		rest   []string
+		err    error
+	}{
+		{
+			[]string{"a", "--output", "x", "b", "--level=3", "-v"},
+			map[string][]string{"output": {"x"}, "level": {"3"}, "verbose": {"true"}},
+			[]string{"a", "b"},
+			nil,
+		},
+		{
+			[]string{"-vqofile", "-lv", "2", "-o=y"},
+			map[string][]string{"verbose": {"true"}, "quiet": {"true"}, "output": {"file", "y"}, "level": {"2"}},
+			[]string{},
+			nil,
+		},
+		{
+			[]string{"-vo", "-", "--no-color", "--", "-q", "--level"},
+			map[string][]string{"verbose": {"true"}, "output": {"-"}, "color": {"false"}},
+			[]string{"-q", "--level"},
+			nil,
+		},
+		{
+			[]string{"-5", "--verbose=false", "-1.5", "-2e3"},
+			map[string][]string{"verbose": {"false"}},
+			[]string{"-5", "-1.5", "-2e3"},
+			nil,
+		},
+		{[]string{"--unknown"}, nil, nil, ErrFlagUnknown},
+		{[]string{"-vx"}, nil, nil, ErrFlagUnknown},
+		{[]string{"--no-output"}, nil, nil, ErrFlagUnknown},
+		{[]string{"a", "--output"}, nil, nil, ErrParamValueMissing},
+	}
+
+	c := newTestCLI()
+
+	for _, testCase := range testCases {
+		line, err := c.tokenize(flags, testCase.args)
+		if testCase.err != nil {
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("tokenize() with %v should have returned %v instead of %v", testCase.args, testCase.err, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("tokenize() with %v returned error: %s", testCase.args, err.Error())
+
+			continue
+		}
+
+		if !reflect.DeepEqual(line.flagValues, testCase.values) || !reflect.DeepEqual(line.args, testCase.rest) {
+			t.Errorf("tokenize() with %v returned %v %v", testCase.args, line.flagValues, line.args)
+		}
+	}
+}
+
+// TestTokenizeAllErrors tests that tokenizing goes on after unknown flags and flags with missing values.
+func TestTokenizeAllErrors(t *testing.T) {
+	t.Parallel()
+
+	flags := map[string]*param{
+		"output": {name: "output", alias: "o", valueType: TypeString},
+		"level":  {name: "level", valueType: TypeInt},
+	}
+
+	c := newTestCLI(WithAllErrors())
+
+	line, err := c.tokenize(flags, []string{"--unknown", "x", "-o", "y", "--level"})
+	if !errors.Is(err, ErrFlagUnknown) || !errors.Is(err, ErrParamValueMissing) {
+		t.Errorf("tokenize() should have returned all errors instead of %v", err)
+	}
+
+	if !reflect.DeepEqual(line.flagValues, map[string][]string{"output": {"y"}}) ||
+		!reflect.DeepEqual(line.args, []string{"x"}) {
+		t.Errorf("tokenize() should treat arg after unknown flag as positional: %v %v", line.flagValues, line.args)
+	}
+}
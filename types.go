@@ -0,0 +1,116 @@
+package broccli
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are formats accepted by TypeTime, from the most specific one.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// sizePattern matches sizes such as 512, 1.5GB, 512MiB or 10k.
+var sizePattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([kKmMgGtTpP]?)(i?)[bB]?$`)
+
+// sizeUnitExponents maps unit prefixes to powers of 1000 (or 1024 for binary units such as MiB).
+var sizeUnitExponents = map[string]float64{"": 0, "k": 1, "m": 2, "g": 3, "t": 4, "p": 5}
+
+// isTypedValue returns true for types that are parsed by parseTypedValue.
+func isTypedValue(valueType int64) bool {
+	return valueType >= TypeDuration && valueType <= TypeRegexp
+}
+
+// parseTypedValue parses value of one of the types such as TypeDuration or TypeURL.
+//
+//nolint:ireturn
+func parseTypedValue(valueType int64, value string) (interface{}, error) {
+	var (
+		parsed interface{}
+		err    error
+	)
+
+	switch valueType {
+	case TypeDuration:
+		parsed, err = time.ParseDuration(value)
+	case TypeTime:
+		parsed, err = parseTime(value)
+	case TypeURL:
+		parsed, err = parseURL(value)
+	case TypeIP:
+		parsed, err = netip.ParseAddr(value)
+	case TypeCIDR:
+		parsed, err = netip.ParsePrefix(value)
+	case TypeSize:
+		parsed, err = parseSize(value)
+	case TypeRegexp:
+		parsed, err = regexp.Compile(value)
+	default:
+		return nil, ErrParamTypeInvalid
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrParamValueInvalid, err.Error())
+	}
+
+	return parsed, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, expected format such as 2006-01-02 or 2006-01-02T15:04:05Z", value)
+}
+
+func parseURL(value string) (*url.URL, error) {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q, scheme and host are required", value)
+	}
+
+	return parsed, nil
+}
+
+// parseSize returns number of bytes.  Units such as kB or MB are powers of 1000, and KiB or MiB are powers of 1024.
+func parseSize(value string) (int64, error) {
+	matches := sizePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid size %q, expected number with optional unit such as 512MiB", value)
+	}
+
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	base := 1000.0
+	if matches[3] != "" {
+		base = 1024
+	}
+
+	size := number * math.Pow(base, sizeUnitExponents[strings.ToLower(matches[2])])
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", value)
+	}
+
+	return int64(size), nil
+}
@@ -0,0 +1,183 @@
+package broccli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestParamValidationTypes tests validation of types such as duration or URL.
+func TestParamValidationTypes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		valueType int64
+		valid     []string
+		invalid   []string
+	}{
+		{TypeDuration, []string{"30s", "1h30m", "-5m"}, []string{"30", "abc"}},
+		{TypeTime, []string{"2024-01-01", "2024-01-01T10:00:00Z", "2024-01-01 10:00:00"}, []string{"01/01/2024"}},
+		{TypeURL, []string{"https://example.com/path?q=1", "ftp://host"}, []string{"example.com", "/path"}},
+		{TypeIP, []string{"10.0.0.1", "::1"}, []string{"10.0.0.256", "host"}},
+		{TypeCIDR, []string{"10.0.0.0/8", "fd00::/8"}, []string{"10.0.0.0", "10.0.0.0/33"}},
+		{TypeSize, []string{"512", "512MiB", "10MB", "1.5G", "10k"}, []string{"MB", "10XB", "-5"}},
+		{TypeRegexp, []string{"^a.*z$", "[0-9]+"}, []string{"[a-", "(abc"}},
+	}
+
+	for _, testCase := range testCases {
+		p := &param{name: "value", valueType: testCase.valueType}
+
+		for _, value := range testCase.valid {
+			if err := p.validateValue(value); err != nil {
+				t.Errorf("Value %s of type %d should validate: %s", value, testCase.valueType, err.Error())
+			}
+		}
+
+		for _, value := range testCase.invalid {
+			if err := p.validateValue(value); !errors.Is(err, ErrParamValueInvalid) {
+				t.Errorf("Value %s of type %d should not validate", value, testCase.valueType)
+			}
+		}
+	}
+
+	p := &param{name: "timeouts", valueType: TypeDuration, flags: AllowMultipleValues | SeparatorSemiColon}
+	if p.validateValue("1s;2m") != nil || p.validateValue("1s;2") == nil {
+		t.Errorf("Multiple durations should be validated one by one")
+	}
+}
+
+// TestParseSize tests converting sizes to bytes.
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	sizes := map[string]int64{
+		"512":    512,
+		"10k":    10000,
+		"10KiB":  10240,
+		"512MiB": 512 * 1024 * 1024,
+		"1.5GB":  1500000000,
+		"2Ti":    2 * 1024 * 1024 * 1024 * 1024,
+	}
+
+	for value, expected := range sizes {
+		got, err := parseSize(value)
+		if err != nil || got != expected {
+			t.Errorf("Size %s should be %d instead of %d", value, expected, got)
+		}
+	}
+
+	// 8192PiB is 2^63 bytes, which does not fit in int64
+	got, err := parseSize("8191PiB")
+	if err != nil || got != 8191*1024*1024*1024*1024*1024 {
+		t.Errorf("Size 8191PiB should be valid: %d, %v", got, err)
+	}
+
+	if _, err = parseSize("8192PiB"); err == nil {
+		t.Errorf("Size 8192PiB should overflow")
+	}
+}
+
+// TestTypedAccessors tests getting values of types such as duration or URL.
+func TestTypedAccessors(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	cmd := c.Command("fetch", "Fetches", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd.Flag("timeout", "t", "DURATION", "Timeout", TypeDuration, 0, WithDefault("30s"))
+	cmd.Flag("since", "", "TIME", "Since", TypeTime, 0)
+	cmd.Flag("cidr", "", "CIDR", "Network", TypeCIDR, 0)
+	cmd.Flag("max-size", "", "SIZE", "Max size", TypeSize, 0)
+	cmd.Flag("match", "", "REGEXP", "Pattern", TypeRegexp, 0)
+	cmd.Arg("endpoint", "URL", "Endpoint", TypeURL, IsRequired)
+	cmd.Arg("ip", "IP", "IP address", TypeIP, 0)
+
+	_, err := c.Parse([]string{
+		"test", "fetch", "--since", "2024-01-01", "--cidr", "10.0.0.0/8", "--max-size", "1KiB", "--match", "^a",
+		"https://example.com", "10.1.2.3",
+	})
+	if err != nil {
+		t.Fatalf("Parse() returned error: %s", err.Error())
+	}
+
+	timeout, err := c.FlagDuration("timeout")
+	if err != nil || timeout != 30*time.Second {
+		t.Errorf("FlagDuration() returned invalid value: %v %v", timeout, err)
+	}
+
+	since, err := c.FlagTime("since")
+	if err != nil || since.Year() != 2024 {
+		t.Errorf("FlagTime() returned invalid value: %v %v", since, err)
+	}
+
+	cidr, err := c.FlagCIDR("cidr")
+	if err != nil || cidr.Bits() != 8 {
+		t.Errorf("FlagCIDR() returned invalid value: %v %v", cidr, err)
+	}
+
+	size, err := c.FlagSize("max-size")
+	if err != nil || size != 1024 {
+		t.Errorf("FlagSize() returned invalid value: %v %v", size, err)
+	}
+
+	match, err := c.FlagRegexp("match")
+	if err != nil || !match.MatchString("abc") {
+		t.Errorf("FlagRegexp() returned invalid value: %v %v", match, err)
+	}
+
+	endpoint, err := c.ArgURL("endpoint")
+	if err != nil || endpoint.Host != "example.com" {
+		t.Errorf("ArgURL() returned invalid value: %v %v", endpoint, err)
+	}
+
+	ip, err := c.ArgIP("ip")
+	if err != nil || !ip.Is4() {
+		t.Errorf("ArgIP() returned invalid value: %v %v", ip, err)
+	}
+
+	_, err = c.FlagDuration("since")
+	if !errors.Is(err, ErrParamTypeMismatch) {
+		t.Errorf("FlagDuration() should have returned ErrParamTypeMismatch instead of %v", err)
+	}
+}
+
+// TestTypedSliceAccessors tests accessors returning all the values of typed params.
+func TestTypedSliceAccessors(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	cmd := c.Command("ping", "Pings", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd.Flag("timeout", "t", "DURATION", "Timeouts", TypeDuration, AllowMultipleValues)
+	cmd.Flag("ip", "", "IP", "IP address", TypeIP, AllowRepeated)
+	cmd.Arg("size", "SIZE", "Packet sizes", TypeSize, 0, Variadic(0, 0))
+
+	_, err := c.Parse([]string{"test", "ping", "--timeout", "1s,2s", "--ip", "1.1.1.1", "--ip", "2.2.2.2", "1KiB", "2"})
+	if err != nil {
+		t.Fatalf("Parse() returned error: %s", err.Error())
+	}
+
+	timeouts, err := c.FlagDurations("timeout")
+	if err != nil || !reflect.DeepEqual(timeouts, []time.Duration{time.Second, 2 * time.Second}) {
+		t.Errorf("FlagDurations() returned invalid values: %v %v", timeouts, err)
+	}
+
+	ips, err := c.FlagIPs("ip")
+	if err != nil || len(ips) != 2 || ips[1].String() != "2.2.2.2" {
+		t.Errorf("FlagIPs() returned invalid values: %v %v", ips, err)
+	}
+
+	sizes, err := c.ArgSizes("size")
+	if err != nil || !reflect.DeepEqual(sizes, []int64{1024, 2}) {
+		t.Errorf("ArgSizes() returned invalid values: %v %v", sizes, err)
+	}
+
+	_, err = c.FlagURLs("ip")
+	if !errors.Is(err, ErrParamTypeMismatch) {
+		t.Errorf("FlagURLs() should have returned ErrParamTypeMismatch instead of %v", err)
+	}
+}
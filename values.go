@@ -0,0 +1,602 @@
+package broccli
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func errParamNotFoundWithName(name string) error {
+	return fmt.Errorf("%w: %s", ErrParamNotFound, name)
+}
+
+func errParamTypeMismatchWithName(name string) error {
+	return fmt.Errorf("%w: %s", ErrParamTypeMismatch, name)
+}
+
+// FlagInt returns value of flag as an integer.  Flag must be of TypeInt.  When flag is empty, 0 is returned.
+func (c *Broccli) FlagInt(name string) (int, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return flag.intValue(c.parsedFlags[name])
+}
+
+// FlagFloat returns value of flag as a float.  Flag must be of TypeFloat or TypeInt.  When flag is empty, 0 is
+// returned.
+func (c *Broccli) FlagFloat(name string) (float64, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return flag.floatValue(c.parsedFlags[name])
+}
+
+// FlagBool returns value of flag as a boolean.  Flag must be of TypeBool.
+func (c *Broccli) FlagBool(name string) (bool, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return false, err
+	}
+
+	return flag.boolValue(c.parsedFlags[name])
+}
+
+// FlagStrings returns value of flag split into a slice.  Separator set on the flag (eg. SeparatorColon) is used
+// when flag has AllowMultipleValues.  Otherwise, slice contains just one element.  When flag has AllowRepeated,
+// values of all occurrences are returned.  When flag is empty, empty slice is returned.
+func (c *Broccli) FlagStrings(name string) ([]string, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.flagValues(flag), nil
+}
+
+// FlagInts works like FlagStrings but returns integers.  Flag must be of TypeInt.
+func (c *Broccli) FlagInts(name string) ([]int, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return flag.intValues(c.flagValues(flag))
+}
+
+// FlagFloats works like FlagStrings but returns floats.  Flag must be of TypeFloat or TypeInt.
+func (c *Broccli) FlagFloats(name string) ([]float64, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return flag.floatValues(c.flagValues(flag))
+}
+
+// ArgInt returns value of arg as an integer.  Arg must be of TypeInt.  When arg is empty, 0 is returned.
+func (c *Broccli) ArgInt(name string) (int, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return arg.intValue(c.parsedArgs[name])
+}
+
+// ArgFloat returns value of arg as a float.  Arg must be of TypeFloat or TypeInt.  When arg is empty, 0 is returned.
+func (c *Broccli) ArgFloat(name string) (float64, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return arg.floatValue(c.parsedArgs[name])
+}
+
+// ArgStrings returns value of arg split into a slice.  It works the same as FlagStrings.  For variadic arg, all the
+// values are returned.
+func (c *Broccli) ArgStrings(name string) ([]string, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.argValues(arg), nil
+}
+
+// ArgInts works like ArgStrings but returns integers.  Arg must be of TypeInt.
+func (c *Broccli) ArgInts(name string) ([]int, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return arg.intValues(c.argValues(arg))
+}
+
+// ArgFloats works like ArgStrings but returns floats.  Arg must be of TypeFloat or TypeInt.
+func (c *Broccli) ArgFloats(name string) ([]float64, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return arg.floatValues(c.argValues(arg))
+}
+
+// FlagDuration returns value of flag as a duration.  Flag must be of TypeDuration.  When flag is empty, 0 is
+// returned.
+func (c *Broccli) FlagDuration(name string) (time.Duration, error) {
+	return flagTypedValue[time.Duration](c, name, TypeDuration)
+}
+
+// FlagTime returns value of flag as a time.  Flag must be of TypeTime.  When flag is empty, zero time is returned.
+func (c *Broccli) FlagTime(name string) (time.Time, error) {
+	return flagTypedValue[time.Time](c, name, TypeTime)
+}
+
+// FlagURL returns value of flag as a URL.  Flag must be of TypeURL.  When flag is empty, nil is returned.
+func (c *Broccli) FlagURL(name string) (*url.URL, error) {
+	return flagTypedValue[*url.URL](c, name, TypeURL)
+}
+
+// FlagIP returns value of flag as an IP address.  Flag must be of TypeIP.  When flag is empty, zero address is
+// returned.
+func (c *Broccli) FlagIP(name string) (netip.Addr, error) {
+	return flagTypedValue[netip.Addr](c, name, TypeIP)
+}
+
+// FlagCIDR returns value of flag as an IP network.  Flag must be of TypeCIDR.  When flag is empty, zero prefix is
+// returned.
+func (c *Broccli) FlagCIDR(name string) (netip.Prefix, error) {
+	return flagTypedValue[netip.Prefix](c, name, TypeCIDR)
+}
+
+// FlagSize returns value of flag as a number of bytes.  Flag must be of TypeSize.  When flag is empty, 0 is returned.
+func (c *Broccli) FlagSize(name string) (int64, error) {
+	return flagTypedValue[int64](c, name, TypeSize)
+}
+
+// FlagRegexp returns value of flag as a compiled regular expression.  Flag must be of TypeRegexp.  When flag is
+// empty, nil is returned.
+func (c *Broccli) FlagRegexp(name string) (*regexp.Regexp, error) {
+	return flagTypedValue[*regexp.Regexp](c, name, TypeRegexp)
+}
+
+// ArgDuration returns value of arg as a duration.  It works the same as FlagDuration.
+func (c *Broccli) ArgDuration(name string) (time.Duration, error) {
+	return argTypedValue[time.Duration](c, name, TypeDuration)
+}
+
+// ArgTime returns value of arg as a time.  It works the same as FlagTime.
+func (c *Broccli) ArgTime(name string) (time.Time, error) {
+	return argTypedValue[time.Time](c, name, TypeTime)
+}
+
+// ArgURL returns value of arg as a URL.  It works the same as FlagURL.
+func (c *Broccli) ArgURL(name string) (*url.URL, error) {
+	return argTypedValue[*url.URL](c, name, TypeURL)
+}
+
+// ArgIP returns value of arg as an IP address.  It works the same as FlagIP.
+func (c *Broccli) ArgIP(name string) (netip.Addr, error) {
+	return argTypedValue[netip.Addr](c, name, TypeIP)
+}
+
+// ArgCIDR returns value of arg as an IP network.  It works the same as FlagCIDR.
+func (c *Broccli) ArgCIDR(name string) (netip.Prefix, error) {
+	return argTypedValue[netip.Prefix](c, name, TypeCIDR)
+}
+
+// ArgSize returns value of arg as a number of bytes.  It works the same as FlagSize.
+func (c *Broccli) ArgSize(name string) (int64, error) {
+	return argTypedValue[int64](c, name, TypeSize)
+}
+
+// ArgRegexp returns value of arg as a compiled regular expression.  It works the same as FlagRegexp.
+func (c *Broccli) ArgRegexp(name string) (*regexp.Regexp, error) {
+	return argTypedValue[*regexp.Regexp](c, name, TypeRegexp)
+}
+
+// FlagDurations works like FlagStrings but returns durations.  Flag must be of TypeDuration.
+func (c *Broccli) FlagDurations(name string) ([]time.Duration, error) {
+	return flagTypedValues[time.Duration](c, name, TypeDuration)
+}
+
+// FlagTimes works like FlagStrings but returns times.  Flag must be of TypeTime.
+func (c *Broccli) FlagTimes(name string) ([]time.Time, error) {
+	return flagTypedValues[time.Time](c, name, TypeTime)
+}
+
+// FlagURLs works like FlagStrings but returns URLs.  Flag must be of TypeURL.
+func (c *Broccli) FlagURLs(name string) ([]*url.URL, error) {
+	return flagTypedValues[*url.URL](c, name, TypeURL)
+}
+
+// FlagIPs works like FlagStrings but returns IP addresses.  Flag must be of TypeIP.
+func (c *Broccli) FlagIPs(name string) ([]netip.Addr, error) {
+	return flagTypedValues[netip.Addr](c, name, TypeIP)
+}
+
+// FlagCIDRs works like FlagStrings but returns IP networks.  Flag must be of TypeCIDR.
+func (c *Broccli) FlagCIDRs(name string) ([]netip.Prefix, error) {
+	return flagTypedValues[netip.Prefix](c, name, TypeCIDR)
+}
+
+// FlagSizes works like FlagStrings but returns numbers of bytes.  Flag must be of TypeSize.
+func (c *Broccli) FlagSizes(name string) ([]int64, error) {
+	return flagTypedValues[int64](c, name, TypeSize)
+}
+
+// FlagRegexps works like FlagStrings but returns compiled regular expressions.  Flag must be of TypeRegexp.
+func (c *Broccli) FlagRegexps(name string) ([]*regexp.Regexp, error) {
+	return flagTypedValues[*regexp.Regexp](c, name, TypeRegexp)
+}
+
+// ArgDurations returns values of arg as durations.  It works the same as FlagDurations.
+func (c *Broccli) ArgDurations(name string) ([]time.Duration, error) {
+	return argTypedValues[time.Duration](c, name, TypeDuration)
+}
+
+// ArgTimes returns values of arg as times.  It works the same as FlagTimes.
+func (c *Broccli) ArgTimes(name string) ([]time.Time, error) {
+	return argTypedValues[time.Time](c, name, TypeTime)
+}
+
+// ArgURLs returns values of arg as URLs.  It works the same as FlagURLs.
+func (c *Broccli) ArgURLs(name string) ([]*url.URL, error) {
+	return argTypedValues[*url.URL](c, name, TypeURL)
+}
+
+// ArgIPs returns values of arg as IP addresses.  It works the same as FlagIPs.
+func (c *Broccli) ArgIPs(name string) ([]netip.Addr, error) {
+	return argTypedValues[netip.Addr](c, name, TypeIP)
+}
+
+// ArgCIDRs returns values of arg as IP networks.  It works the same as FlagCIDRs.
+func (c *Broccli) ArgCIDRs(name string) ([]netip.Prefix, error) {
+	return argTypedValues[netip.Prefix](c, name, TypeCIDR)
+}
+
+// ArgSizes returns values of arg as numbers of bytes.  It works the same as FlagSizes.
+func (c *Broccli) ArgSizes(name string) ([]int64, error) {
+	return argTypedValues[int64](c, name, TypeSize)
+}
+
+// ArgRegexps returns values of arg as compiled regular expressions.  It works the same as FlagRegexps.
+func (c *Broccli) ArgRegexps(name string) ([]*regexp.Regexp, error) {
+	return argTypedValues[*regexp.Regexp](c, name, TypeRegexp)
+}
+
+// FlagParsed returns value of flag converted with ValueType set with WithType, or value of one of the types such as
+// TypeDuration.  Flag must not allow multiple values.  When flag is empty, nil is returned.
+//
+//nolint:ireturn
+func (c *Broccli) FlagParsed(name string) (interface{}, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return flag.parsedValue(c.parsedFlags[name])
+}
+
+// ArgParsed returns value of arg converted with its ValueType.  It works the same as FlagParsed.
+//
+//nolint:ireturn
+func (c *Broccli) ArgParsed(name string) (interface{}, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return arg.parsedValue(c.parsedArgs[name])
+}
+
+// EnvValue returns value of environment variable that was validated before running the command.
+func (c *Broccli) EnvValue(name string) string {
+	return c.parsedEnv[name]
+}
+
+// EnvInt returns value of environment variable as an integer.  Environment variable must be of TypeInt.
+func (c *Broccli) EnvInt(name string) (int, error) {
+	env, err := c.envParam(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return env.intValue(c.parsedEnv[name])
+}
+
+// EnvFloat returns value of environment variable as a float.  Environment variable must be of TypeFloat or TypeInt.
+func (c *Broccli) EnvFloat(name string) (float64, error) {
+	env, err := c.envParam(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return env.floatValue(c.parsedEnv[name])
+}
+
+// EnvBool returns value of environment variable as a boolean.  Environment variable must be of TypeBool.
+func (c *Broccli) EnvBool(name string) (bool, error) {
+	env, err := c.envParam(name)
+	if err != nil {
+		return false, err
+	}
+
+	return env.boolValue(c.parsedEnv[name])
+}
+
+// EnvStrings returns value of environment variable split into a slice.  It works the same as FlagStrings.
+func (c *Broccli) EnvStrings(name string) ([]string, error) {
+	env, err := c.envParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.stringValues(c.parsedEnv[name]), nil
+}
+
+// flagValues returns values of flag split into a slice.  Values of repeated flags are split one by one so that
+// an occurrence containing separator is not split when flag does not allow multiple values.
+func (c *Broccli) flagValues(flag *param) []string {
+	occurrences, ok := c.parsedRepeated[flag.name]
+	if !ok {
+		return flag.stringValues(c.parsedFlags[flag.name])
+	}
+
+	values := []string{}
+	for _, occurrence := range occurrences {
+		values = append(values, flag.stringValues(occurrence)...)
+	}
+
+	return values
+}
+
+// argValues returns values of arg split into a slice.  Values of variadic arg are split one by one, the same as
+// values of repeated flags.
+func (c *Broccli) argValues(arg *param) []string {
+	variadicValues, ok := c.parsedVariadic[arg.name]
+	if !ok {
+		return arg.stringValues(c.parsedArgs[arg.name])
+	}
+
+	values := []string{}
+	for _, value := range variadicValues {
+		values = append(values, arg.stringValues(value)...)
+	}
+
+	return values
+}
+
+func (c *Broccli) flagParam(name string) (*param, error) {
+	if c.command == nil {
+		return nil, errParamNotFoundWithName(name)
+	}
+
+	flag, ok := c.command.allFlags()[name]
+	if !ok {
+		return nil, errParamNotFoundWithName(name)
+	}
+
+	return flag, nil
+}
+
+func (c *Broccli) argParam(name string) (*param, error) {
+	if c.command == nil {
+		return nil, errParamNotFoundWithName(name)
+	}
+
+	arg, ok := c.command.args[name]
+	if !ok {
+		return nil, errParamNotFoundWithName(name)
+	}
+
+	return arg, nil
+}
+
+func (c *Broccli) envParam(name string) (*param, error) {
+	if env, ok := c.env[name]; ok {
+		return env, nil
+	}
+
+	if c.command != nil {
+		if env, ok := c.command.env[name]; ok {
+			return env, nil
+		}
+	}
+
+	return nil, errParamNotFoundWithName(name)
+}
+
+func flagTypedValue[T any](c *Broccli, name string, valueType int64) (T, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return typedValue[T](flag, valueType, c.parsedFlags[name])
+}
+
+func argTypedValue[T any](c *Broccli, name string, valueType int64) (T, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return typedValue[T](arg, valueType, c.parsedArgs[name])
+}
+
+// typedValue returns value of one of the types such as TypeDuration or TypeURL.
+func typedValue[T any](p *param, valueType int64, value string) (T, error) {
+	var zero T
+
+	if p.valueType != valueType {
+		return zero, errParamTypeMismatchWithName(p.name)
+	}
+
+	if value == "" {
+		return zero, nil
+	}
+
+	parsed, err := parseTypedValue(valueType, value)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, _ := parsed.(T)
+
+	return typed, nil
+}
+
+func flagTypedValues[T any](c *Broccli, name string, valueType int64) ([]T, error) {
+	flag, err := c.flagParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedValues[T](flag, valueType, c.flagValues(flag))
+}
+
+func argTypedValues[T any](c *Broccli, name string, valueType int64) ([]T, error) {
+	arg, err := c.argParam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedValues[T](arg, valueType, c.argValues(arg))
+}
+
+// typedValues returns values of one of the types such as TypeDuration or TypeURL.
+func typedValues[T any](p *param, valueType int64, values []string) ([]T, error) {
+	if p.valueType != valueType {
+		return nil, errParamTypeMismatchWithName(p.name)
+	}
+
+	typed := make([]T, len(values))
+
+	for i, v := range values {
+		value, err := typedValue[T](p, valueType, v)
+		if err != nil {
+			return nil, err
+		}
+
+		typed[i] = value
+	}
+
+	return typed, nil
+}
+
+func (p *param) intValue(value string) (int, error) {
+	if p.valueType != TypeInt {
+		return 0, errParamTypeMismatchWithName(p.name)
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrParamValueInvalid, err.Error())
+	}
+
+	return i, nil
+}
+
+func (p *param) floatValue(value string) (float64, error) {
+	if p.valueType != TypeFloat && p.valueType != TypeInt {
+		return 0, errParamTypeMismatchWithName(p.name)
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrParamValueInvalid, err.Error())
+	}
+
+	return f, nil
+}
+
+func (p *param) boolValue(value string) (bool, error) {
+	if p.valueType != TypeBool {
+		return false, errParamTypeMismatchWithName(p.name)
+	}
+
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrParamValueInvalid, err.Error())
+	}
+
+	return b, nil
+}
+
+func (p *param) stringValues(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	if p.flags&AllowMultipleValues == 0 {
+		return []string{value}
+	}
+
+	return strings.Split(value, p.separator())
+}
+
+func (p *param) intValues(values []string) ([]int, error) {
+	if p.valueType != TypeInt {
+		return nil, errParamTypeMismatchWithName(p.name)
+	}
+
+	ints := make([]int, len(values))
+
+	for i, v := range values {
+		intValue, err := p.intValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		ints[i] = intValue
+	}
+
+	return ints, nil
+}
+
+func (p *param) floatValues(values []string) ([]float64, error) {
+	if p.valueType != TypeFloat && p.valueType != TypeInt {
+		return nil, errParamTypeMismatchWithName(p.name)
+	}
+
+	floats := make([]float64, len(values))
+
+	for i, v := range values {
+		floatValue, err := p.floatValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		floats[i] = floatValue
+	}
+
+	return floats, nil
+}
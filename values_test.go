@@ -0,0 +1,120 @@
+package broccli
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestTypedValues tests getting flag, arg and env var values as specific types.
+func TestTypedValues(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI(WithLookupEnv(func(key string) (string, bool) {
+		if key == "RATIO" {
+			return "0.5", true
+		}
+
+		return "", false
+	}))
+	cmd := c.Command("cmd", "Checks values", func(_ context.Context, c *Broccli) int {
+		count, err := c.FlagInt("count")
+		if err != nil || count != 12 {
+			return 10
+		}
+
+		verbose, err := c.FlagBool("verbose")
+		if err != nil || !verbose {
+			return 11
+		}
+
+		ids, err := c.FlagInts("ids")
+		if err != nil || len(ids) != 3 || ids[2] != 3 {
+			return 12
+		}
+
+		names, err := c.FlagStrings("names")
+		if err != nil || len(names) != 2 || names[1] != "b" {
+			return 13
+		}
+
+		ratio, err := c.ArgFloat("ratio")
+		if err != nil || ratio != 1.5 {
+			return 14
+		}
+
+		envRatio, err := c.EnvFloat("RATIO")
+		if err != nil || envRatio != 0.5 || c.EnvValue("RATIO") != "0.5" {
+			return 15
+		}
+
+		_, err = c.FlagInt("names")
+		if !errors.Is(err, ErrParamTypeMismatch) {
+			return 16
+		}
+
+		_, err = c.FlagInt("missing")
+		if !errors.Is(err, ErrParamNotFound) {
+			return 17
+		}
+
+		return 2
+	})
+	cmd.Flag("count", "c", "NUM", "Count", TypeInt, IsRequired)
+	cmd.Flag("verbose", "v", "", "Verbose", TypeBool, 0)
+	cmd.Flag("ids", "", "IDS", "IDs", TypeInt, AllowMultipleValues)
+	cmd.Flag("names", "", "NAMES", "Names", TypeAlphanumeric, AllowMultipleValues|SeparatorColon)
+	cmd.Arg("ratio", "RATIO", "Ratio", TypeFloat, IsRequired)
+	cmd.Env("RATIO", "Ratio", TypeFloat, 0)
+
+	got := c.RunArgs(
+		context.Background(),
+		[]string{"test", "cmd", "-c", "12", "-v", "--ids", "1,2,3", "--names", "a:b", "1.5"},
+	)
+	if got != 2 {
+		t.Errorf("CLI.RunArgs() should have returned 2 instead of %d", got)
+	}
+}
+
+// TestRepeatedFlags tests flags that can be passed more than once.
+func TestRepeatedFlags(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCLI()
+	cmd := c.Command("cmd", "Checks values", func(_ context.Context, _ *Broccli) int {
+		return 0
+	})
+	cmd.Flag("tag", "t", "TAG", "Tag", TypeString, AllowRepeated|IsRequired)
+	cmd.Flag("port", "p", "PORT", "Ports", TypeInt, AllowRepeated|AllowMultipleValues)
+
+	_, err := c.Parse([]string{"test", "cmd", "--tag", "a,b", "-t", "c", "--tag", "d", "-p", "80,443", "--port", "8080"})
+	if err != nil {
+		t.Fatalf("Parse() returned error: %s", err.Error())
+	}
+
+	tags, err := c.FlagStrings("tag")
+	if err != nil || len(tags) != 3 || tags[0] != "a,b" || tags[2] != "d" {
+		t.Errorf("FlagStrings() returned invalid values: %v", tags)
+	}
+
+	ports, err := c.FlagInts("port")
+	if err != nil || len(ports) != 3 || ports[0] != 80 || ports[2] != 8080 {
+		t.Errorf("FlagInts() returned invalid values: %v", ports)
+	}
+
+	if c.Flag("port") != "80,443,8080" {
+		t.Errorf("Flag() returned invalid value: %s", c.Flag("port"))
+	}
+
+	_, err = c.Parse([]string{"test", "cmd", "-t", "a", "-p", "80", "-p", "http"})
+
+	var paramErr *ParamError
+	if !errors.As(err, &paramErr) || paramErr.Name != "port" || paramErr.Value != "http" {
+		t.Errorf("Parse() should have returned error for invalid occurrence instead of %v", err)
+	}
+
+	_, err = c.Parse([]string{"test", "cmd"})
+	if !errors.Is(err, ErrParamValueMissing) {
+		t.Errorf("Parse() should have returned ErrParamValueMissing instead of %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package broccli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValueType is a custom type of param value, eg. a semantic version or a cloud region.  It is set with WithType
+// option on a param of TypeCustom, and it is used for validation, help screen and shell completion.
+type ValueType interface {
+	// Parse converts a single value to the type.  When param allows multiple values, each of them is parsed
+	// separately.  Returned error makes the value invalid.
+	Parse(value string) (interface{}, error)
+	// Validate checks the parsed value, eg. whether it is one of the supported ones.
+	Validate(value interface{}) error
+	// Placeholder returns placeholder of the value that is used when param is defined without one.
+	Placeholder() string
+	// Complete returns candidates for shell completion of the value.  It can return nil.
+	Complete(current string) []string
+}
+
+// Validator is a function that checks a single value of param once the value passed the type validation.  When param
+// allows multiple values, each of them is checked separately.  Returned error makes the value invalid.
+type Validator func(value string) error
+
+func errParamValueInvalidWithCause(err error) error {
+	if errors.Is(err, ErrParamValueInvalid) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrParamValueInvalid, err)
+}
+
+// parseCustomValue parses and validates a single value of TypeCustom.
+//
+//nolint:ireturn
+func parseCustomValue(valueType ValueType, value string) (interface{}, error) {
+	parsed, err := valueType.Parse(value)
+	if err != nil {
+		return nil, errParamValueInvalidWithCause(err)
+	}
+
+	err = valueType.Validate(parsed)
+	if err != nil {
+		return nil, errParamValueInvalidWithCause(err)
+	}
+
+	return parsed, nil
+}
+
+// parsedValue returns value of param converted with its ValueType, or with one of the types such as TypeDuration.
+//
+//nolint:ireturn
+func (p *param) parsedValue(value string) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	switch {
+	case p.valueType == TypeCustom:
+		return parseCustomValue(p.options.valueType, value)
+	case isTypedValue(p.valueType):
+		return parseTypedValue(p.valueType, value)
+	default:
+		return nil, errParamTypeMismatchWithName(p.name)
+	}
+}
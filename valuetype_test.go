@@ -0,0 +1,138 @@
+package broccli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var errRegionUnsupported = errors.New("unsupported region")
+
+// regionType is a ValueType of region names such as eu-west-1.
+type regionType struct{}
+
+type region struct {
+	area   string
+	number int
+}
+
+func (regionType) Parse(value string) (interface{}, error) {
+	idx := strings.LastIndex(value, "-")
+	if idx < 1 {
+		return nil, errors.New("expected format area-number")
+	}
+
+	number, err := strconv.Atoi(value[idx+1:])
+	if err != nil {
+		return nil, errors.New("expected format area-number")
+	}
+
+	return region{area: value[:idx], number: number}, nil
+}
+
+func (regionType) Validate(value interface{}) error {
+	if r, _ := value.(region); r.area != "eu-west" && r.area != "us-east" {
+		return errRegionUnsupported
+	}
+
+	return nil
+}
+
+func (regionType) Placeholder() string {
+	return "REGION"
+}
+
+func (regionType) Complete(_ string) []string {
+	return []string{"eu-west-1", "us-east-1"}
+}
+
+// TestCustomValueType tests params with ValueType set with WithType.
+func TestCustomValueType(t *testing.T) {
+	t.Parallel()
+
+	cli := newTestCLI()
+	cmd := cli.Command("deploy", "Deploys the app", func(_ context.Context, _ *Broccli) int { return 0 })
+	cmd.Flag("region", "r", "", "Region to deploy to", TypeCustom, AllowMultipleValues, WithType(regionType{}))
+	cmd.Arg("primary", "", "Primary region", TypeCustom, IsRequired, WithType(regionType{}))
+
+	if cmd.flags["region"].valuePlaceholder != "REGION" || cmd.args["primary"].valuePlaceholder != "REGION" {
+		t.Errorf("Placeholder should be taken from value type")
+	}
+
+	_, err := cli.Parse([]string{"test", "deploy", "-r", "eu-west-1,us-east-2", "us-east-1"})
+	if err != nil {
+		t.Fatalf("Parse() returned error: %s", err.Error())
+	}
+
+	primary, err := cli.ArgParsed("primary")
+	if err != nil || !reflect.DeepEqual(primary, region{area: "us-east", number: 1}) {
+		t.Errorf("ArgParsed() returned invalid value %v: %v", primary, err)
+	}
+
+	_, err = cli.FlagParsed("missing")
+	if !errors.Is(err, ErrParamNotFound) {
+		t.Errorf("FlagParsed() should have returned error for unknown flag")
+	}
+
+	_, err = cli.Parse([]string{"test", "deploy", "-r", "eu-west-1,ap-south-1", "us-east-1"})
+	if !errors.Is(err, errRegionUnsupported) || !errors.Is(err, ErrParamValueInvalid) ||
+		err.Error() != "Flag region: param value invalid: unsupported region" {
+		t.Errorf("Parse() should have returned value type error instead of %v", err)
+	}
+
+	_, err = cli.Parse([]string{"test", "deploy", "eu"})
+	if err == nil || err.Error() != "Argument REGION: param value invalid: expected format area-number" {
+		t.Errorf("Parse() should have returned parse error instead of %v", err)
+	}
+
+	candidates := cli.completions([]string{"deploy", "-r", "eu"})
+	if !reflect.DeepEqual(candidates, []string{"eu-west-1"}) {
+		t.Errorf("Completion should use value type: %v", candidates)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Flag() should have panicked when TypeCustom has no value type")
+		}
+	}()
+
+	cmd.Flag("zone", "", "ZONE", "Zone", TypeCustom, 0)
+}
+
+// TestParamValidators tests validators set with WithValidator.
+func TestParamValidators(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	p := &param{name: "version", valueType: TypeString, flags: AllowMultipleValues}
+	WithValidator(
+		func(value string) error {
+			calls++
+
+			if !strings.HasPrefix(value, "v") {
+				return errors.New("must start with v")
+			}
+
+			return nil
+		},
+	)(&p.options)
+
+	if p.validateValue("v1.0.0,v2.0.0") != nil || p.validateValue("") != nil || calls != 2 {
+		t.Errorf("Valid values should pass validators")
+	}
+
+	err := p.validateValue("v1.0.0,2.0.0")
+	if !errors.Is(err, ErrParamValueInvalid) || err.Error() != "param value invalid: must start with v" {
+		t.Errorf("Validator error should make value invalid: %v", err)
+	}
+
+	p = &param{name: "count", valueType: TypeInt}
+	WithValidator(func(_ string) error { return ErrParamValueInvalid })(&p.options)
+
+	if p.validateValue("x") == nil || p.validateValue("3") == nil {
+		t.Errorf("Validators should be called after type validation")
+	}
+}